@@ -17,6 +17,25 @@ type Main struct {
 	Timestamp  int64  `flag:"t" help:"A Unix timestamp"`
 }
 
+// Runner is implemented by the flag structs of all commands.
+type Runner interface {
+	Run() error
+}
+
+// Subcommands maps the name of a subcommand to the constructor of its flags.
+var Subcommands = map[string]func() Runner{
+	"convert":  func() Runner { return NewConvert() },
+	"daemon":   func() Runner { return NewDaemon() },
+	"decrypt":  func() Runner { return NewDecrypt() },
+	"encrypt":  func() Runner { return NewEncrypt() },
+	"range":    func() Runner { return NewRange() },
+	"serve":    func() Runner { return NewServe() },
+	"sign":     func() Runner { return NewSign() },
+	"stats":    func() Runner { return NewStats() },
+	"validate": func() Runner { return NewValidate() },
+	"verify":   func() Runner { return NewVerify() },
+}
+
 func NewMain() *Main {
 	return &Main{Values: false, RandomPart: "", Random: false, Number: 1}
 }
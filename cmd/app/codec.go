@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Crypt holds the flags shared by the encrypt and decrypt subcommands, see
+// timeflake.Codec.
+type Crypt struct {
+	ID           string    `help:"Timeflake to convert, reads one per line from stdin if empty"`
+	Key          string    `help:"Hex encoded AES key, defaults to $TIMEFLAKE_KEY"`
+	PreviousKeys []string  `help:"Comma separated, hex encoded keys used before --key"`
+	KeyIndex     int       `help:"Key the IDs were encrypted with when decrypting: 0 for --key, 1 for the first previous key"`
+	To           string    `help:"Output encoding: base62, hex, int, ulid or uuid"`
+	In           io.Reader `flag:"-"`
+	Out          io.Writer `flag:"-"`
+	decrypt      bool
+}
+
+func NewEncrypt() *Crypt {
+	return &Crypt{To: "base62", In: os.Stdin, Out: os.Stdout}
+}
+
+func NewDecrypt() *Crypt {
+	return &Crypt{To: "base62", In: os.Stdin, Out: os.Stdout, decrypt: true}
+}
+
+func (c *Crypt) Run() error {
+	codec, err := c.codec()
+	if err != nil {
+		return err
+	}
+	convert := func(value string) error {
+		tf, err := timeflake.Parse(value)
+		if err != nil {
+			return err
+		}
+		if c.decrypt {
+			tf, err = codec.DecryptKey(tf, c.KeyIndex)
+		} else {
+			tf, err = codec.Encrypt(tf)
+		}
+		if err != nil {
+			return err
+		}
+		out, err := encode(tf, c.To)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.Out, out)
+		return err
+	}
+
+	if c.ID != "" {
+		return convert(c.ID)
+	}
+	var failed error
+	err = scanLines(c.In, func(line int, value string) {
+		if failed == nil {
+			failed = convert(value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return failed
+}
+
+func (c *Crypt) codec() (*timeflake.Codec, error) {
+	key := c.Key
+	if key == "" {
+		key = os.Getenv("TIMEFLAKE_KEY")
+	}
+	if key == "" {
+		return nil, errors.New("missing --key or $TIMEFLAKE_KEY")
+	}
+	primary, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("key must be hex encoded")
+	}
+	var previous [][]byte
+	for _, k := range c.PreviousKeys {
+		b, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, errors.New("previous keys must be hex encoded")
+		}
+		previous = append(previous, b)
+	}
+	return timeflake.NewCodec(primary, previous...)
+}
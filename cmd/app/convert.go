@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Convert reads timeflakes in any format understood by timeflake.Parse and
+// prints them in another encoding. With --time-uuid, version 1 and 6 UUIDs
+// are imported by their timestamp instead, see timeflake.FromTimeUUID.
+type Convert struct {
+	ID       string    `help:"Timeflake to convert, reads one per line from stdin if empty"`
+	To       string    `help:"Output encoding: base62, hex, int, ulid or uuid"`
+	TimeUUID bool      `flag:"time-uuid" help:"Import the timestamp of version 1 and 6 UUIDs"`
+	Random   bool      `help:"Fill the random part of imported UUIDs randomly instead of deriving it from the UUID"`
+	In       io.Reader `flag:"-"`
+	Out      io.Writer `flag:"-"`
+}
+
+func NewConvert() *Convert {
+	return &Convert{To: "base62", In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *Convert) Run() error {
+	if c.ID != "" {
+		return c.convert(c.ID)
+	}
+	var failed error
+	err := scanLines(c.In, func(line int, value string) {
+		if failed == nil {
+			failed = c.convert(value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return failed
+}
+
+func (c *Convert) convert(value string) error {
+	var tf *timeflake.Timeflake
+	var err error
+	if c.TimeUUID {
+		u, parseErr := uuid.Parse(value)
+		if parseErr != nil {
+			return errors.New("can not parse UUID '" + value + "'")
+		}
+		tf, err = timeflake.FromTimeUUID(u, !c.Random)
+	} else {
+		tf, err = timeflake.Parse(value)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := encode(tf, c.To)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.Out, out)
+	return err
+}
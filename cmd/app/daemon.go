@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/gioni06/go-timeflake/pkg/daemon"
+)
+
+// Daemon issues timeflakes over a Unix domain socket until it receives
+// SIGINT or SIGTERM. See the daemon package for the protocol.
+type Daemon struct {
+	Socket string `help:"Path of the Unix domain socket"`
+	Mode   string `help:"Permissions of the socket, in octal"`
+	State  string `help:"File that keeps the highest issued timestamp across restarts"`
+}
+
+func NewDaemon() *Daemon {
+	return &Daemon{Socket: "/tmp/timeflake.sock", Mode: "0660"}
+}
+
+func (d *Daemon) Run() error {
+	mode, err := strconv.ParseUint(d.Mode, 8, 32)
+	if err != nil {
+		return fmt.Errorf("can not parse mode '%s'", d.Mode)
+	}
+	l, err := daemon.Listen(d.Socket, os.FileMode(mode))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(d.Socket)
+
+	srv := daemon.NewServer(newGenerator(d.State))
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(l)
+	}()
+	fmt.Printf("listening on %s\n", d.Socket)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
+	return srv.Close()
+}
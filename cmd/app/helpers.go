@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// encode returns the Timeflake in the requested output encoding, one of the
+// names of timeflake.Formats.
+func encode(tf *timeflake.Timeflake, encoding string) (string, error) {
+	if encoding == "" {
+		encoding = "base62"
+	}
+	f, err := timeflake.LookupFormat(strings.ToLower(encoding))
+	if err != nil {
+		return "", err
+	}
+	return f.Encode(tf), nil
+}
+
+// parseInterval accepts Go durations as well as the keywords minute, hour,
+// day and week.
+func parseInterval(value string) (time.Duration, error) {
+	switch strings.ToLower(value) {
+	case "minute":
+		return time.Minute, nil
+	case "hour":
+		return time.Hour, nil
+	case "day":
+		return 24 * time.Hour, nil
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Millisecond {
+		return 0, fmt.Errorf("can not parse interval '%s'", value)
+	}
+	return d, nil
+}
+
+// reserveAhead is how far ahead long running subcommands reserve timestamps
+// in their state file.
+const reserveAhead = time.Second
+
+// newGenerator returns a Generator that persists its state in the file
+// state, if it is set.
+func newGenerator(state string) *timeflake.Generator {
+	if state == "" {
+		return timeflake.NewGenerator()
+	}
+	return timeflake.NewGenerator(timeflake.WithStateStore(timeflake.NewFileState(state), reserveAhead))
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/utils"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Range prints the smallest and largest Timeflake of a time window. The
+// window includes From and excludes To.
+//
+// With SQL the bounds default to hex. Base62 mixes upper and lower case, so
+// its order only matches the order of the IDs under a case-sensitive
+// (binary) collation.
+type Range struct {
+	From     string    `help:"Start of the window (RFC 3339, date or Unix timestamp)"`
+	To       string    `help:"End of the window, exclusive (RFC 3339, date or Unix timestamp)"`
+	Encoding string    `flag:"e" help:"Output encoding: base62, hex, int or uuid, defaults to hex with --sql and base62 otherwise"`
+	SQL      string    `flag:"sql" help:"Print a BETWEEN predicate for the given column"`
+	Step     string    `help:"Print the bounds of every bucket of this interval, e.g. 'hour', 'day' or '15m'"`
+	Out      io.Writer `flag:"-"`
+}
+
+func NewRange() *Range {
+	return &Range{From: "", To: "now", Encoding: "", Out: os.Stdout}
+}
+
+func (r *Range) Run() error {
+	if r.From == "" {
+		return errors.New("missing --from")
+	}
+	from, err := utils.ParseTime(r.From)
+	if err != nil {
+		return err
+	}
+	to, err := utils.ParseTime(r.To)
+	if err != nil {
+		return err
+	}
+	if !from.Before(to) {
+		return errors.New("--from must be before --to")
+	}
+
+	if r.Step == "" {
+		return r.print(from, to)
+	}
+
+	step, err := parseInterval(r.Step)
+	if err != nil {
+		return err
+	}
+	for start := from; start.Before(to); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(to) {
+			end = to
+		}
+		if err := r.print(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// print writes the bounds of the window [from, to).
+func (r *Range) print(from, to time.Time) error {
+	min, max, err := timeflake.RangeFor(from, to)
+	if err != nil {
+		return err
+	}
+	encoding := r.Encoding
+	if encoding == "" && r.SQL != "" {
+		encoding = "hex"
+	}
+	lo, err := encode(min, encoding)
+	if err != nil {
+		return err
+	}
+	hi, err := encode(max, encoding)
+	if err != nil {
+		return err
+	}
+
+	if r.SQL != "" {
+		if strings.ToLower(encoding) != "int" {
+			lo, hi = "'"+lo+"'", "'"+hi+"'"
+		}
+		_, err = fmt.Fprintf(r.Out, "%s BETWEEN %s AND %s\n", r.SQL, lo, hi)
+		return err
+	}
+	_, err = fmt.Fprintf(r.Out, "from=%s\tto=%s\tmin=%s\tmax=%s\t\n",
+		from.Format(time.RFC3339Nano),
+		to.Format(time.RFC3339Nano),
+		lo,
+		hi,
+	)
+	return err
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/server"
+)
+
+// Serve issues timeflakes over HTTP until it receives SIGINT or SIGTERM.
+type Serve struct {
+	Listen          string        `flag:"listen" help:"Address to listen on"`
+	ShutdownTimeout time.Duration `help:"How long to wait for open requests on shutdown"`
+	State           string        `help:"File that keeps the highest issued timestamp across restarts"`
+}
+
+func NewServe() *Serve {
+	return &Serve{Listen: ":8080", ShutdownTimeout: 10 * time.Second}
+}
+
+func (s *Serve) Run() error {
+	srv := &http.Server{
+		Addr:              s.Listen,
+		Handler:           server.New(newGenerator(s.State)),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	fmt.Printf("listening on %s\n", s.Listen)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
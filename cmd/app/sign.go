@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Token holds the flags shared by the sign and verify subcommands, see
+// timeflake.Signer.
+type Token struct {
+	ID           string    `help:"Timeflake to sign or token to verify, reads one per line from stdin if empty"`
+	Key          string    `help:"Hex encoded signing key, defaults to $TIMEFLAKE_SIGNING_KEY"`
+	PreviousKeys []string  `help:"Comma separated, hex encoded keys also accepted when verifying"`
+	TagLength    int       `help:"Length of the tag in Bytes"`
+	To           string    `help:"Output encoding of verified timeflakes: base62, hex, int, ulid or uuid"`
+	In           io.Reader `flag:"-"`
+	Out          io.Writer `flag:"-"`
+	verify       bool
+}
+
+func NewSign() *Token {
+	return &Token{TagLength: 8, To: "base62", In: os.Stdin, Out: os.Stdout}
+}
+
+func NewVerify() *Token {
+	return &Token{TagLength: 8, To: "base62", In: os.Stdin, Out: os.Stdout, verify: true}
+}
+
+func (t *Token) Run() error {
+	signer, err := t.signer()
+	if err != nil {
+		return err
+	}
+	convert := func(value string) error {
+		var out string
+		if t.verify {
+			tf, err := signer.Verify(value)
+			if err != nil {
+				return err
+			}
+			if out, err = encode(tf, t.To); err != nil {
+				return err
+			}
+		} else {
+			tf, err := timeflake.Parse(value)
+			if err != nil {
+				return err
+			}
+			out = signer.Sign(tf)
+		}
+		_, err := fmt.Fprintln(t.Out, out)
+		return err
+	}
+
+	if t.ID != "" {
+		return convert(t.ID)
+	}
+	var failed error
+	err = scanLines(t.In, func(line int, value string) {
+		if failed == nil {
+			failed = convert(value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return failed
+}
+
+func (t *Token) signer() (*timeflake.Signer, error) {
+	key := t.Key
+	if key == "" {
+		key = os.Getenv("TIMEFLAKE_SIGNING_KEY")
+	}
+	if key == "" {
+		return nil, errors.New("missing --key or $TIMEFLAKE_SIGNING_KEY")
+	}
+	primary, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("key must be hex encoded")
+	}
+	var previous [][]byte
+	for _, k := range t.PreviousKeys {
+		b, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, errors.New("previous keys must be hex encoded")
+		}
+		previous = append(previous, b)
+	}
+	return timeflake.NewSigner(t.TagLength, primary, previous...)
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Stats buckets a list of timeflakes, one per line, by their creation time.
+// The IDs are streamed and one counter per bucket is kept in memory. The peak
+// per millisecond takes one counter per distinct millisecond as well, so
+// memory grows with the time span of the input, by about 40 MB per million
+// distinct milliseconds. With --ordered only the current millisecond is
+// counted and IDs that go backwards start a new count.
+type Stats struct {
+	Input    string    `flag:"i" help:"File with one timeflake per line, '-' reads stdin"`
+	Interval string    `help:"Bucket size, e.g. 'minute', 'hour', 'day' or '15m'"`
+	JSON     bool      `flag:"json" help:"Print the statistics as JSON"`
+	Ordered  bool      `help:"The input should be ordered, report timeflakes that go backwards in time. Without it the peak per millisecond keeps one counter, about 40 Bytes, per distinct millisecond in memory"`
+	In       io.Reader `flag:"-"`
+	Out      io.Writer `flag:"-"`
+}
+
+func NewStats() *Stats {
+	return &Stats{Input: "-", Interval: "hour", In: os.Stdin, Out: os.Stdout}
+}
+
+type statsBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+	Min   time.Time `json:"min"`
+	Max   time.Time `json:"max"`
+}
+
+type statsGap struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type statsReport struct {
+	Total     int           `json:"total"`
+	Malformed int           `json:"malformed"`
+	Min       time.Time     `json:"min"`
+	Max       time.Time     `json:"max"`
+	PeakPerMs int           `json:"peak_per_ms"`
+	SharedMs  int           `json:"shared_ms"`
+	Backwards int           `json:"backwards"`
+	Buckets   []statsBucket `json:"buckets"`
+	Gaps      []statsGap    `json:"gaps"`
+}
+
+func (s *Stats) Run() error {
+	interval, err := parseInterval(s.Interval)
+	if err != nil {
+		return err
+	}
+
+	in := s.In
+	if s.Input != "-" {
+		f, err := os.Open(s.Input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var report statsReport
+	buckets := make(map[int64]*statsBucket)
+	perMs := make(map[int64]int)
+	var previous time.Time
+	// The run of IDs of the current millisecond in ordered input.
+	var runMs int64
+	var run int
+	countMs := func(n int) {
+		if n > report.PeakPerMs {
+			report.PeakPerMs = n
+		}
+		if n > 1 {
+			report.SharedMs += n
+		}
+	}
+
+	err = scanLines(in, func(line int, value string) {
+		tf, err := timeflake.Parse(value)
+		if err != nil {
+			report.Malformed++
+			return
+		}
+		created := tf.Time()
+		report.Total++
+
+		if report.Total == 1 || created.Before(report.Min) {
+			report.Min = created
+		}
+		if report.Total == 1 || created.After(report.Max) {
+			report.Max = created
+		}
+		if s.Ordered && created.Before(previous) {
+			report.Backwards++
+		}
+		previous = created
+
+		if ms := created.UnixMilli(); !s.Ordered {
+			perMs[ms]++
+		} else if run > 0 && ms == runMs {
+			run++
+		} else {
+			countMs(run)
+			runMs, run = ms, 1
+		}
+
+		start := created.Truncate(interval)
+		b, ok := buckets[start.UnixMilli()]
+		if !ok {
+			b = &statsBucket{Start: start, Min: created, Max: created}
+			buckets[start.UnixMilli()] = b
+		}
+		b.Count++
+		if created.Before(b.Min) {
+			b.Min = created
+		}
+		if created.After(b.Max) {
+			b.Max = created
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	countMs(run)
+	for _, n := range perMs {
+		countMs(n)
+	}
+
+	report.Buckets = make([]statsBucket, 0, len(buckets))
+	for _, b := range buckets {
+		report.Buckets = append(report.Buckets, *b)
+	}
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		return report.Buckets[i].Start.Before(report.Buckets[j].Start)
+	})
+	report.Gaps = []statsGap{}
+	for i := 1; i < len(report.Buckets); i++ {
+		end := report.Buckets[i-1].Start.Add(interval)
+		if end.Before(report.Buckets[i].Start) {
+			report.Gaps = append(report.Gaps, statsGap{From: end, To: report.Buckets[i].Start})
+		}
+	}
+
+	if s.JSON {
+		enc := json.NewEncoder(s.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return s.printTable(&report)
+}
+
+func (s *Stats) printTable(report *statsReport) error {
+	w := tabwriter.NewWriter(s.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tCOUNT\tMIN\tMAX")
+	for _, b := range report.Buckets {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n",
+			b.Start.Format(time.RFC3339),
+			b.Count,
+			b.Min.Format(time.RFC3339Nano),
+			b.Max.Format(time.RFC3339Nano),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, g := range report.Gaps {
+		fmt.Fprintf(s.Out, "gap\tfrom=%s\tto=%s\t\n", g.From.Format(time.RFC3339), g.To.Format(time.RFC3339))
+	}
+	_, err := fmt.Fprintf(s.Out, "total=%d\tmalformed=%d\tmin=%s\tmax=%s\tpeak_per_ms=%d\tshared_ms=%d\tbackwards=%d\tgaps=%d\t\n",
+		report.Total,
+		report.Malformed,
+		report.Min.Format(time.RFC3339Nano),
+		report.Max.Format(time.RFC3339Nano),
+		report.PeakPerMs,
+		report.SharedMs,
+		report.Backwards,
+		len(report.Gaps),
+	)
+	return err
+}
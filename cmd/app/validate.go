@@ -0,0 +1,243 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/bloom"
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/internal/utils"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Validate audits a list of timeflakes, one per line, in any of the formats
+// understood by timeflake.Parse.
+//
+// Files are read twice to find duplicates: the first pass collects candidates
+// with a Bloom filter sized for the length of the input, the second pass
+// confirms them. Only the candidates are kept in memory. Input that can not
+// be rewound, like a pipe, is copied to a temporary file first.
+//
+// The random part of a timeflake takes all of its 80 bits, so every parsed
+// timeflake has a valid one. Values too wide for 128 bits are malformed.
+type Validate struct {
+	Input     string    `flag:"i" help:"File with one timeflake per line, '-' reads stdin"`
+	NotBefore string    `help:"Report timeflakes created before this time"`
+	NotAfter  string    `help:"Report timeflakes created after this time"`
+	Quiet     bool      `flag:"q" help:"Only print the summary"`
+	In        io.Reader `flag:"-"`
+	Out       io.Writer `flag:"-"`
+}
+
+func NewValidate() *Validate {
+	return &Validate{Input: "-", NotAfter: "now", In: os.Stdin, Out: os.Stdout}
+}
+
+type validateSummary struct {
+	ids, valid, malformed, duplicates, tooOld, future int
+}
+
+func (s *validateSummary) problems() int {
+	return s.malformed + s.duplicates + s.tooOld + s.future
+}
+
+func (v *Validate) Run() error {
+	const op = "app:Validate"
+	var notBefore, notAfter time.Time
+	var err error
+	if v.NotBefore != "" {
+		if notBefore, err = utils.ParseTime(v.NotBefore); err != nil {
+			return err
+		}
+	}
+	if v.NotAfter != "" {
+		if notAfter, err = utils.ParseTime(v.NotAfter); err != nil {
+			return err
+		}
+	}
+
+	in := v.In
+	if v.Input != "-" {
+		f, err := os.Open(v.Input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	// Redirected stdin can be rewound like a file, other input is spooled.
+	seeker, canSeek := in.(io.Seeker)
+	var start, size int64
+	if canSeek {
+		start, size, err = inputSize(seeker)
+		canSeek = err == nil
+	}
+	if !canSeek {
+		spool, err := spoolInput(in)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+		if start, size, err = inputSize(spool); err != nil {
+			return err
+		}
+		in, seeker = spool, spool
+	}
+	candidates := make(map[[16]byte]int)
+	// A base62 timeflake and its line break take 23 Bytes, the other
+	// formats more, so the filter has room for every line.
+	filter := bloom.New(uint64(size/23), 0.001)
+
+	var summary validateSummary
+
+	err = scanLines(in, func(line int, value string) {
+		summary.ids++
+		tf, err := timeflake.Parse(value)
+		if err != nil {
+			summary.malformed++
+			v.report(line, value, "malformed: "+err.Error())
+			return
+		}
+		summary.valid++
+
+		created := tf.Time()
+		if !notBefore.IsZero() && created.Before(notBefore) {
+			summary.tooOld++
+			v.report(line, value, "created before "+notBefore.Format(time.RFC3339Nano))
+		}
+		if !notAfter.IsZero() && created.After(notAfter) {
+			summary.future++
+			v.report(line, value, "created after "+notAfter.Format(time.RFC3339Nano))
+		}
+
+		var key [16]byte
+		copy(key[:], tf.Bytes)
+		if filter.Add(key[:]) {
+			candidates[key] = 0
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) > 0 {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		err = scanLines(in, func(line int, value string) {
+			tf, err := timeflake.Parse(value)
+			if err != nil {
+				return
+			}
+			var key [16]byte
+			copy(key[:], tf.Bytes)
+			first, ok := candidates[key]
+			if !ok {
+				return
+			}
+			if first == 0 {
+				candidates[key] = line
+				return
+			}
+			summary.duplicates++
+			v.report(line, value, fmt.Sprintf("duplicate of line %d", first))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(v.Out, "ids=%d\tvalid=%d\tmalformed=%d\tduplicates=%d\ttoo_old=%d\tfuture=%d\t\n",
+		summary.ids,
+		summary.valid,
+		summary.malformed,
+		summary.duplicates,
+		summary.tooOld,
+		summary.future,
+	)
+
+	if n := summary.problems(); n > 0 {
+		return &customerr.ValidationError{
+			Err: fmt.Errorf("found %d problems in %d timeflakes", n, summary.ids),
+			Op:  op,
+		}
+	}
+	return nil
+}
+
+func (v *Validate) report(line int, value string, problem string) {
+	if !v.Quiet {
+		fmt.Fprintf(v.Out, "line=%d\tid=%s\tproblem=%s\t\n", line, value, problem)
+	}
+}
+
+// inputSize returns the current offset of s and the number of Bytes after
+// it, leaving s at the offset.
+func inputSize(s io.Seeker) (start, size int64, err error) {
+	if start, err = s.Seek(0, io.SeekCurrent); err != nil {
+		return 0, 0, err
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := s.Seek(start, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	return start, end - start, nil
+}
+
+// spoolInput copies r to a temporary file, which the caller removes.
+func spoolInput(r io.Reader) (*os.File, error) {
+	f, err := os.CreateTemp("", "timeflake-validate-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, r); err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+		if err == nil {
+			return f, nil
+		}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil, err
+}
+
+// maxLine bounds the memory used for a single line. Longer lines are cut off
+// and passed to fn like any other value, which then fails to parse.
+const maxLine = 64 * 1024
+
+// scanLines calls fn for every non-empty line of r. Line numbers start at 1.
+func scanLines(r io.Reader, fn func(line int, value string)) error {
+	reader := bufio.NewReaderSize(r, maxLine)
+	line := 0
+	for {
+		b, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			value := strings.TrimSpace(string(b[:64])) + "..."
+			for err == bufio.ErrBufferFull {
+				_, err = reader.ReadSlice('\n')
+			}
+			line++
+			fn(line, value)
+		} else if len(b) > 0 {
+			line++
+			if value := strings.TrimSpace(string(b)); value != "" {
+				fn(line, value)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
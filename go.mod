@@ -1,8 +1,8 @@
 module github.com/gioni06/go-timeflake
 
-go 1.15
+go 1.18
 
 require (
 	github.com/google/uuid v1.2.0
 	github.com/jaffee/commandeer v0.5.0
-)
\ No newline at end of file
+)
@@ -3,4 +3,6 @@ package alphabets
 const (
 	BASE62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 	HEX    = "0123456789abcdef"
+	// Crockford's Base32 as used by ULID, without I, L, O and U.
+	CROCKFORD32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
 )
@@ -0,0 +1,51 @@
+// The bloom package provides a small Bloom filter that is used to find
+// candidates for duplicates in large inputs without keeping every key in memory.
+package bloom
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+type Filter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// New creates a filter for n keys with the given false positive rate.
+func New(n uint64, falsePositiveRate float64) *Filter {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &Filter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Add inserts the key and reports whether it may have been added before.
+func (f *Filter) Add(key []byte) bool {
+	h := fnv.New128a()
+	h.Write(key)
+	sum := h.Sum(nil)
+	h1 := binary.BigEndian.Uint64(sum[0:8])
+	h2 := binary.BigEndian.Uint64(sum[8:16]) | 1
+
+	seen := true
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if f.bits[word]&mask == 0 {
+			seen = false
+			f.bits[word] |= mask
+		}
+	}
+	return seen
+}
@@ -42,3 +42,67 @@ func (r *UUIDError) Error() string {
 func (r *UUIDError) Operation() string {
 	return r.Op
 }
+
+type ValidationError struct {
+	Err error
+	Op  string
+}
+
+func (r *ValidationError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *ValidationError) Operation() string {
+	return r.Op
+}
+
+// ConfigError is returned for invalid layouts, options and their
+// combinations.
+type ConfigError struct {
+	Err error
+	Op  string
+}
+
+func (r *ConfigError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *ConfigError) Operation() string {
+	return r.Op
+}
+
+// GeneratorError wraps failures of a Generator, like a failing entropy source.
+type GeneratorError struct {
+	Err error
+	Op  string
+}
+
+func (r *GeneratorError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *GeneratorError) Operation() string {
+	return r.Op
+}
+
+func (r *GeneratorError) Unwrap() error {
+	return r.Err
+}
+
+// TokenError is returned when a signed ID token fails verification.
+type TokenError struct {
+	Err error
+	Op  string
+}
+
+func (r *TokenError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *TokenError) Operation() string {
+	return r.Op
+}
+
+func (r *TokenError) Unwrap() error {
+	return r.Err
+}
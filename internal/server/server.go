@@ -0,0 +1,217 @@
+// The server package exposes a Generator over HTTP for services that can not
+// use the Go library directly. All responses are JSON.
+//
+//	GET  /v1/id?encoding=hex           issue one timeflake
+//	GET  /v1/ids?count=10&encoding=hex issue a batch of timeflakes
+//	POST /v1/parse                     parse and inspect a posted timeflake
+//	GET  /healthz                      health check
+//	GET  /metrics                      request and issue counters
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// MaxCount limits the number of timeflakes issued by a single batch request.
+const MaxCount = 10000
+
+type Server struct {
+	// Counters come first to keep them 64-bit aligned for sync/atomic.
+	requests uint64
+	issued   uint64
+	parsed   uint64
+	failures uint64
+
+	gen     *timeflake.Generator
+	started time.Time
+	mux     *http.ServeMux
+}
+
+// New creates a Server that issues timeflakes from gen. All requests share
+// the Generator, so issued timeflakes are strictly increasing.
+func New(gen *timeflake.Generator) *Server {
+	s := &Server{gen: gen, started: time.Now(), mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/id", s.handleID)
+	s.mux.HandleFunc("/v1/ids", s.handleIDs)
+	s.mux.HandleFunc("/v1/parse", s.handleParse)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddUint64(&s.requests, 1)
+	s.mux.ServeHTTP(w, r)
+}
+
+type idsResponse struct {
+	IDs []string `json:"ids"`
+}
+
+type idResponse struct {
+	ID string `json:"id"`
+}
+
+type parseResponse struct {
+	Base62    string `json:"base62"`
+	Hex       string `json:"hex"`
+	Int       string `json:"int"`
+	UUID      string `json:"uuid"`
+	Timestamp int64  `json:"timestamp"`
+	Time      string `json:"time"`
+	Random    string `json:"random"`
+}
+
+type metricsResponse struct {
+	Requests      uint64  `json:"requests"`
+	Issued        uint64  `json:"issued"`
+	Parsed        uint64  `json:"parsed"`
+	Failures      uint64  `json:"failures"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleID(w http.ResponseWriter, r *http.Request) {
+	if !s.allow(w, r, http.MethodGet) {
+		return
+	}
+	ids, ok := s.issue(w, r, 1)
+	if ok {
+		s.write(w, http.StatusOK, idResponse{ID: ids[0]})
+	}
+}
+
+func (s *Server) handleIDs(w http.ResponseWriter, r *http.Request) {
+	if !s.allow(w, r, http.MethodGet) {
+		return
+	}
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxCount {
+			s.fail(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", MaxCount))
+			return
+		}
+		count = n
+	}
+	ids, ok := s.issue(w, r, count)
+	if ok {
+		s.write(w, http.StatusOK, idsResponse{IDs: ids})
+	}
+}
+
+// issue creates count timeflakes in the encoding requested by the query.
+func (s *Server) issue(w http.ResponseWriter, r *http.Request, count int) ([]string, bool) {
+	encoding := r.URL.Query().Get("encoding")
+	if encoding == "" {
+		encoding = "base62"
+	}
+	format, err := timeflake.LookupFormat(encoding)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	flakes, err := s.gen.NextNContext(r.Context(), count)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		s.fail(w, http.StatusServiceUnavailable, err.Error())
+		return nil, false
+	}
+	if err != nil {
+		s.fail(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	ids := make([]string, len(flakes))
+	for i, f := range flakes {
+		ids[i] = format.Encode(f)
+	}
+	atomic.AddUint64(&s.issued, uint64(len(ids)))
+	return ids, true
+}
+
+// handleParse accepts the timeflake either as plain text or as a JSON object
+// of the form {"id": "..."}.
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	if !s.allow(w, r, http.MethodPost) {
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1024))
+	if err != nil {
+		s.fail(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	value := strings.TrimSpace(string(body))
+	if strings.HasPrefix(value, "{") {
+		var req idResponse
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.fail(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		value = req.ID
+	}
+
+	tf, err := timeflake.Parse(value)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	atomic.AddUint64(&s.parsed, 1)
+	created := tf.Time()
+	s.write(w, http.StatusOK, parseResponse{
+		Base62:    tf.Base62,
+		Hex:       tf.Hex,
+		Int:       tf.Int.String(),
+		UUID:      tf.UUID,
+		Timestamp: created.UnixMilli(),
+		Time:      created.Format(time.RFC3339Nano),
+		Random:    tf.Rand(),
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.write(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.write(w, http.StatusOK, metricsResponse{
+		Requests:      atomic.LoadUint64(&s.requests),
+		Issued:        atomic.LoadUint64(&s.issued),
+		Parsed:        atomic.LoadUint64(&s.parsed),
+		Failures:      atomic.LoadUint64(&s.failures),
+		UptimeSeconds: time.Since(s.started).Seconds(),
+	})
+}
+
+func (s *Server) allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	s.fail(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func (s *Server) fail(w http.ResponseWriter, status int, msg string) {
+	atomic.AddUint64(&s.failures, 1)
+	s.write(w, status, errorResponse{Error: msg})
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
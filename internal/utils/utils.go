@@ -3,25 +3,27 @@
 package utils
 
 import (
-	"math"
+	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // The 'strconv' package provides a Itoa function, but it can only deal with Int values.
-// This converts a big.Int number to a string for a given alphabet.
+// This converts a big.Int number to a string for a given alphabet and left pads it
+// with the first character of the alphabet to at least padding characters.
 func BigIntToASCII(value *big.Int, alphabet string, padding int) (string, error) {
 	alphabetSlice := strings.Split(alphabet, "")
 	v := new(big.Int)
 	v.SetBytes(value.Bytes())
 
 	z := big.NewInt(0)
+	result := ""
 	if v.Cmp(z) == 0 {
-		return alphabetSlice[0], nil
+		result = alphabetSlice[0]
 	}
 
-	var result string
-
 	for v.Cmp(z) != 0 {
 		rem := big.NewInt(0)
 		base := big.NewInt(int64(len(alphabet)))
@@ -29,9 +31,8 @@ func BigIntToASCII(value *big.Int, alphabet string, padding int) (string, error)
 		result = alphabetSlice[rem.Int64()] + result
 	}
 
-	if padding != 0 {
-		fill := math.Max(float64(padding-len(result)), 0)
-		result = FillString(alphabetSlice[0], int(fill)) + result
+	if fill := padding - len(result); fill > 0 {
+		result = FillString(alphabetSlice[0], fill) + result
 	}
 
 	return result, nil
@@ -61,10 +62,9 @@ func ASCIIToBigInt(value string, alphabet string) *big.Int {
 
 // Fills a string with a given character.
 func FillString(char string, length int) string {
-	o := char
-	n := o
-	for i := 1; i < length; i++ {
-		n += o
+	n := ""
+	for i := 0; i < length; i++ {
+		n += char
 	}
 	return n
 }
@@ -77,3 +77,20 @@ func IndexAlphabet(alphabet []string) map[string]int {
 	}
 	return res
 }
+
+// ParseTime accepts RFC 3339 timestamps, plain dates, Unix timestamps in
+// seconds and the keyword 'now'.
+func ParseTime(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now().UTC(), nil
+	}
+	if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can not parse time '%s'", value)
+}
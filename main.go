@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/rand"
+	"os"
 	"time"
 
 	"github.com/jaffee/commandeer"
@@ -25,7 +27,7 @@ func color(colorString string) func(...interface{}) string {
 }
 
 func main() {
-	err := commandeer.Run(app.NewMain())
+	err := run(os.Args[1:])
 	if err != nil {
 		switch err.(type) {
 		case *customerr.OutOfBoundsError:
@@ -34,12 +36,31 @@ func main() {
 			fmt.Printf(Yellow("%s, converting the inputs to a timeflake failed\n"), err.Error())
 		case *customerr.UUIDError:
 			fmt.Printf(Yellow("%s, the timeflake can not be converted to a valid uuid\n"), err.Error())
+		case *customerr.TokenError:
+			fmt.Printf(Yellow("%s, the token can not be trusted\n"), err.Error())
+		case *customerr.ValidationError:
+			fmt.Printf(Yellow("%s\n"), err.Error())
+		case *customerr.ConfigError:
+			fmt.Printf(Yellow("%s, check the configuration\n"), err.Error())
 		default:
 			fmt.Println(Red(err.Error()))
 		}
+		os.Exit(1)
 	}
 }
 
+// run dispatches to a subcommand if the first argument names one and falls
+// back to the flag based main command otherwise.
+func run(args []string) error {
+	if len(args) > 0 {
+		if newCommand, ok := app.Subcommands[args[0]]; ok {
+			flags := flag.NewFlagSet(args[0], flag.ExitOnError)
+			return commandeer.RunArgs(flags, newCommand(), args[1:])
+		}
+	}
+	return commandeer.Run(app.NewMain())
+}
+
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
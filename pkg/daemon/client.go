@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Client talks to a daemon over its Unix domain socket. It is safe for
+// concurrent use, requests are sent one after another.
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Dial connects to the daemon listening at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Next returns the next timeflake of the daemon.
+func (c *Client) Next() (*timeflake.Timeflake, error) {
+	flakes, err := c.NextN(1)
+	if err != nil {
+		return nil, err
+	}
+	return flakes[0], nil
+}
+
+// NextN returns the next n timeflakes of the daemon.
+func (c *Client) NextN(n int) ([]*timeflake.Timeflake, error) {
+	res, err := c.do(fmt.Sprintf("NEXT %d", n))
+	if err != nil {
+		return nil, err
+	}
+	return parseAll(strings.Fields(res))
+}
+
+// Parse returns the components of id as reported by the daemon.
+func (c *Client) Parse(id string) (map[string]string, error) {
+	res, err := c.do("PARSE " + id)
+	if err != nil {
+		return nil, err
+	}
+	components := make(map[string]string)
+	for _, field := range strings.Fields(res) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) == 2 {
+			components[kv[0]] = kv[1]
+		}
+	}
+	return components, nil
+}
+
+// Range returns the smallest and largest timeflake of the window [from, to).
+func (c *Client) Range(from, to time.Time) (*timeflake.Timeflake, *timeflake.Timeflake, error) {
+	res, err := c.do(fmt.Sprintf("RANGE %s %s", from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano)))
+	if err != nil {
+		return nil, nil, err
+	}
+	flakes, err := parseAll(strings.Fields(res))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(flakes) != 2 {
+		return nil, nil, fmt.Errorf("unexpected response '%s'", res)
+	}
+	return flakes[0], flakes[1], nil
+}
+
+// do sends a request and returns the response without its "OK" status.
+func (c *Client) do(request string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintln(c.conn, request); err != nil {
+		return "", err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "ERR ") {
+		return "", errors.New(strings.TrimPrefix(line, "ERR "))
+	}
+	if line != "OK" && !strings.HasPrefix(line, "OK ") {
+		return "", fmt.Errorf("unexpected response '%s'", line)
+	}
+	return strings.TrimPrefix(line, "OK "), nil
+}
+
+func parseAll(ids []string) ([]*timeflake.Timeflake, error) {
+	flakes := make([]*timeflake.Timeflake, len(ids))
+	for i, id := range ids {
+		f, err := timeflake.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		flakes[i] = f
+	}
+	return flakes, nil
+}
@@ -0,0 +1,230 @@
+// The daemon package serves timeflakes over a Unix domain socket with a
+// line based protocol, for co-located processes that find HTTP too heavy.
+//
+// Every request is a single line, every response is a single line starting
+// with either "OK" or "ERR":
+//
+//	NEXT                 OK <id>
+//	NEXT <n>             OK <id> <id> ...
+//	PARSE <id>           OK base62=<..> hex=<..> int=<..> uuid=<..> ts=<..> rand=<..>
+//	RANGE <from> <to>    OK <min> <max>
+//
+// IDs are base62 encoded. RANGE accepts RFC 3339 timestamps or Unix
+// timestamps in seconds and excludes <to>, like the range subcommand.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gioni06/go-timeflake/internal/utils"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// MaxCount limits the number of timeflakes issued by a single NEXT request.
+const MaxCount = 10000
+
+// Server shares one Generator between all clients, so every timeflake issued
+// on the host is greater than the ones issued before.
+type Server struct {
+	gen *timeflake.Generator
+	// ctx is cancelled by Close, so requests waiting for the Generator
+	// give up.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewServer(gen *timeflake.Generator) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{gen: gen, ctx: ctx, cancel: cancel, conns: make(map[net.Conn]struct{})}
+}
+
+// Listen creates the socket at path with the given permissions. The socket
+// never exists with looser permissions. A stale socket left behind by a
+// crashed daemon is removed, a socket that still accepts connections or
+// anything at path that is not a socket is not.
+func Listen(path string, mode os.FileMode) (net.Listener, error) {
+	info, err := os.Lstat(path)
+	switch {
+	case err == nil:
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another daemon", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	restore := restrictUmask(mode)
+	l, err := net.Listen("unix", path)
+	restore()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve accepts connections on l until Close is called.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.New("server is closed")
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting connections, closes open connections and waits for
+// their handlers to return.
+func (s *Server) Close() error {
+	s.cancel()
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	w := bufio.NewWriter(conn)
+	for scanner.Scan() {
+		fmt.Fprintln(w, s.execute(scanner.Text()))
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// execute runs a single request and returns the response line.
+func (s *Server) execute(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "ERR empty request"
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "NEXT":
+		n := 1
+		if len(fields) == 2 {
+			v, err := strconv.Atoi(fields[1])
+			if err != nil || v < 1 || v > MaxCount {
+				return fmt.Sprintf("ERR count must be between 1 and %d", MaxCount)
+			}
+			n = v
+		} else if len(fields) > 2 {
+			return "ERR usage: NEXT [n]"
+		}
+		flakes, err := s.gen.NextNContext(s.ctx, n)
+		if err != nil {
+			return "ERR " + err.Error()
+		}
+		ids := make([]string, len(flakes))
+		for i, f := range flakes {
+			ids[i] = f.Base62
+		}
+		return "OK " + strings.Join(ids, " ")
+
+	case "PARSE":
+		if len(fields) != 2 {
+			return "ERR usage: PARSE <id>"
+		}
+		tf, err := timeflake.Parse(fields[1])
+		if err != nil {
+			return "ERR " + err.Error()
+		}
+		created := tf.Time()
+		return fmt.Sprintf("OK base62=%s hex=%s int=%s uuid=%s ts=%d rand=%s",
+			tf.Base62,
+			tf.Hex,
+			tf.Int.String(),
+			tf.UUID,
+			created.UnixMilli(),
+			tf.Rand(),
+		)
+
+	case "RANGE":
+		if len(fields) != 3 {
+			return "ERR usage: RANGE <from> <to>"
+		}
+		from, err := utils.ParseTime(fields[1])
+		if err != nil {
+			return "ERR " + err.Error()
+		}
+		to, err := utils.ParseTime(fields[2])
+		if err != nil {
+			return "ERR " + err.Error()
+		}
+		min, max, err := timeflake.RangeFor(from, to)
+		if err != nil {
+			return "ERR " + err.Error()
+		}
+		return "OK " + min.Base62 + " " + max.Base62
+	}
+	return fmt.Sprintf("ERR unknown command '%s'", fields[0])
+}
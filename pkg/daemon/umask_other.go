@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package daemon
+
+import "os"
+
+// restrictUmask does nothing on systems without a umask.
+func restrictUmask(perm os.FileMode) func() {
+	return func() {}
+}
@@ -0,0 +1,23 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package daemon
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// umaskMu serializes changes of the umask, which is shared by the process.
+var umaskMu sync.Mutex
+
+// restrictUmask makes files be created with at most the permissions perm
+// until the returned function restores the umask.
+func restrictUmask(perm os.FileMode) func() {
+	umaskMu.Lock()
+	old := syscall.Umask(int(^perm & os.ModePerm))
+	return func() {
+		syscall.Umask(old)
+		umaskMu.Unlock()
+	}
+}
@@ -0,0 +1,265 @@
+package timeflake
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// BatchOption configures NewBatch and FillBatch.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	sameTimestamp bool
+	gapBits       uint
+}
+
+// SameTimestamp makes all IDs of a batch share one timestamp, and fraction
+// for layouts with FractionBits. If the batch does not fit into the rest of
+// the random part of the current timestamp, it moves to the next one. Batches
+// of the shards of a ShardedGenerator always do this.
+func SameTimestamp() BatchOption {
+	return func(c *batchConfig) {
+		c.sameTimestamp = true
+	}
+}
+
+// RandomGaps makes consecutive IDs of a batch differ by a random amount
+// between 1 and 2^bits instead of by one, so the random part of an ID can not
+// be guessed from its neighbours. bits must be at most 32.
+func RandomGaps(bits uint) BatchOption {
+	return func(c *batchConfig) {
+		c.gapBits = bits
+	}
+}
+
+// NewBatch returns n increasing IDs. It is NewBatchContext without a
+// deadline.
+func (g *Generator) NewBatch(n int, opts ...BatchOption) ([]ID, error) {
+	return g.NewBatchContext(context.Background(), n, opts...)
+}
+
+// NewBatchContext returns n increasing IDs, see FillBatchContext.
+func (g *Generator) NewBatchContext(ctx context.Context, n int, opts ...BatchOption) ([]ID, error) {
+	ids := make([]ID, n)
+	if err := g.fillBatchContext(ctx, ids, opts, "timeflake:Generator.NewBatchContext"); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FillBatch is FillBatchContext without a deadline.
+func (g *Generator) FillBatch(ids []ID, opts ...BatchOption) error {
+	return g.FillBatchContext(context.Background(), ids, opts...)
+}
+
+// FillBatchContext fills ids with IDs greater than every ID the Generator returned
+// before, in strictly increasing order. Only the first ID is created like
+// Next does, the others follow it in the random part, which makes a batch
+// much cheaper than as many calls to Next and does not allocate per ID.
+//
+// If the random part of a timestamp is exhausted, the batch continues in the
+// next one without waiting for the clock, also with WithClockWait. Later
+// calls with WithClockWait then wait for the clock to catch up, until ctx is
+// done.
+func (g *Generator) FillBatchContext(ctx context.Context, ids []ID, opts ...BatchOption) error {
+	return g.fillBatchContext(ctx, ids, opts, "timeflake:Generator.FillBatchContext")
+}
+
+func (g *Generator) fillBatchContext(ctx context.Context, ids []ID, opts []BatchOption, op string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var cfg batchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.gapBits > 32 {
+		return &customerr.ConfigError{
+			Err: errors.New("random gaps must be at most 32 bits"),
+			Op:  op,
+		}
+	}
+
+	return g.retry(ctx, op, func() (time.Duration, error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		first, wait, err := g.next(op)
+		if wait > 0 || err != nil {
+			return wait, err
+		}
+		// A carry would change the shard of an ID.
+		if cfg.sameTimestamp || g.shardBits > 0 {
+			if first, err = g.fitBatch(first, len(ids), cfg.gapBits, op); err != nil {
+				return 0, err
+			}
+		}
+		ids[0] = first
+		if err := g.fillBatch(ids, cfg.gapBits, op); err != nil {
+			return 0, err
+		}
+		return 0, g.advanceTo(ids[len(ids)-1], op)
+	})
+}
+
+// randomBits returns the width of the part of the IDs that is incremented.
+func (g *Generator) randomBits() uint {
+	if g.v7 {
+		return v7RandomBits
+	}
+	return g.layout.RandomBits - g.layout.FractionBits
+}
+
+// random returns the part of id that is incremented, without the shard.
+func (g *Generator) random(id ID) *big.Int {
+	random := g.layout.Random(id)
+	if g.v7 {
+		random = unpackUUIDv7(random)
+	}
+	if g.shardBits > 0 {
+		mask := new(big.Int).Lsh(big.NewInt(1), g.randomBits()-g.shardBits)
+		random.And(random, mask.Sub(mask, big.NewInt(1)))
+	}
+	return random
+}
+
+// fitBatch makes sure n IDs starting at first fit into its timestamp. If
+// they do not, the timestamp is given up and the batch starts in the lower
+// half of the random part of the next one, without waiting for the clock.
+// On failure the state of the Generator is left as it was.
+func (g *Generator) fitBatch(first ID, n int, gapBits uint, op string) (ID, error) {
+	width := g.randomBits() - g.shardBits
+	span := new(big.Int).Lsh(big.NewInt(int64(n-1)), gapBits)
+	fits := func(id ID) bool {
+		return uint(new(big.Int).Add(g.random(id), span).BitLen()) <= width
+	}
+	if fits(first) {
+		return first, nil
+	}
+
+	last, lastMs, lastFrac, wait := g.last, g.lastMs, g.lastFrac, g.wait
+	g.last, g.wait = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), width), big.NewInt(1)), false
+	first, _, err := g.next(op)
+	g.wait = wait
+	if err == nil {
+		// Clear the top bit of the random part. The random part of a
+		// UUIDv7 continues above the version and variant bits after 62
+		// bits.
+		bit := width - 1
+		if g.v7 && bit >= 62 {
+			bit += 2
+		}
+		first[15-bit/8] &^= 1 << (bit % 8)
+		if !fits(first) {
+			err = &customerr.OutOfBoundsError{
+				Err: errors.New("batch does not fit into one timestamp"),
+				Op:  op,
+			}
+		}
+	}
+	if err != nil {
+		g.last, g.lastMs, g.lastFrac = last, lastMs, lastFrac
+		return ID{}, err
+	}
+	return first, nil
+}
+
+// fillBatch fills ids[1:] with the successors of ids[0].
+func (g *Generator) fillBatch(ids []ID, gapBits uint, op string) error {
+	var gaps [64]byte
+	used := len(gaps)
+	for i := 1; i < len(ids); i++ {
+		delta := uint64(1)
+		if gapBits > 0 {
+			if used == len(gaps) {
+				if _, err := io.ReadFull(g.entropy, gaps[:]); err != nil {
+					return &customerr.GeneratorError{
+						Err: err,
+						Op:  op,
+					}
+				}
+				used = 0
+			}
+			delta += uint64(binary.BigEndian.Uint32(gaps[used:])) >> (32 - gapBits)
+			used += 4
+		}
+
+		var ok bool
+		if g.v7 {
+			ids[i], ok = addUUIDv7(ids[i-1], delta)
+		} else {
+			ids[i], ok = addID(ids[i-1], delta, g.layout.Width())
+		}
+		if !ok {
+			return &customerr.OutOfBoundsError{
+				Err: errors.New("time is outside of the layout range"),
+				Op:  op,
+			}
+		}
+	}
+	return nil
+}
+
+// advanceTo makes id the last ID of the Generator.
+func (g *Generator) advanceTo(id ID, op string) error {
+	ms, frac, _ := g.layout.split(id)
+	if g.store != nil && ms > g.reserved {
+		if err := g.store.Reserve(ms + g.ahead); err != nil {
+			return &customerr.GeneratorError{
+				Err: err,
+				Op:  op,
+			}
+		}
+		g.reserved = ms + g.ahead
+	}
+	g.lastMs, g.lastFrac, g.last = ms, frac, g.random(id)
+	return nil
+}
+
+// addID adds delta to id, carrying from the random part into the fraction
+// and the timestamp. It reports false if the result is wider than width.
+func addID(id ID, delta uint64, width uint) (ID, bool) {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	lo, carry := bits.Add64(lo, delta, 0)
+	hi, carry = bits.Add64(hi, 0, carry)
+	n := bits.Len64(lo)
+	if hi != 0 {
+		n = 64 + bits.Len64(hi)
+	}
+	if carry != 0 || uint(n) > width {
+		return id, false
+	}
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, true
+}
+
+// addUUIDv7 adds delta to the random part of a UUIDv7, keeping the version
+// and variant bits. It reports false if the timestamp overflows.
+func addUUIDv7(id ID, delta uint64) (ID, bool) {
+	const mask62 = 1<<62 - 1
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	randB := lo&mask62 + delta
+	randA := hi&0xfff + randB>>62
+	ts := hi >> 16
+	if randA > 0xfff {
+		ts += randA >> 12
+		randA &= 0xfff
+		if ts >= 1<<48 {
+			return id, false
+		}
+	}
+	binary.BigEndian.PutUint64(id[:8], ts<<16|0x7000|randA)
+	binary.BigEndian.PutUint64(id[8:], 2<<62|randB&mask62)
+	return id, true
+}
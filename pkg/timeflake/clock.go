@@ -0,0 +1,133 @@
+package timeflake
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// MonotonicClock is a clock that reads the wall time once and then advances
+// it with the monotonic clock, so steps of the wall clock do not make it jump
+// backwards or far forwards. Use it with WithClock(c.Now).
+//
+// When the wall clock drifts more than the slew threshold away, the clock is
+// resynchronized gradually: it runs up to the maximum slew rate faster or
+// slower than the monotonic clock until the drift is gone. With the default
+// rate of 500ppm, a drift of one second takes about 33 minutes to correct.
+type MonotonicClock struct {
+	mu        sync.Mutex
+	wall      func() time.Time
+	elapsed   func() time.Duration
+	threshold time.Duration
+	maxSlew   float64
+
+	anchor      time.Time
+	offset      time.Duration
+	lastElapsed time.Duration
+	slewing     bool
+}
+
+// ClockOption configures a MonotonicClock.
+type ClockOption func(*MonotonicClock)
+
+// WithWallClock replaces time.Now as the source of the wall time.
+func WithWallClock(wall func() time.Time) ClockOption {
+	return func(c *MonotonicClock) {
+		c.wall = wall
+	}
+}
+
+// WithElapsed replaces the monotonic clock. elapsed returns the time passed
+// since the MonotonicClock was created and must never decrease.
+func WithElapsed(elapsed func() time.Duration) ClockOption {
+	return func(c *MonotonicClock) {
+		c.elapsed = elapsed
+	}
+}
+
+// WithSlewThreshold sets the drift from the wall clock at which the clock
+// starts to resynchronize. The default is 100ms.
+func WithSlewThreshold(d time.Duration) ClockOption {
+	return func(c *MonotonicClock) {
+		c.threshold = d
+	}
+}
+
+// WithMaxSlew sets the maximum rate at which the clock runs faster or slower
+// to resynchronize, as a fraction of the elapsed time. It must be below 1 so
+// the clock never runs backwards. The default is 0.0005.
+func WithMaxSlew(rate float64) ClockOption {
+	return func(c *MonotonicClock) {
+		c.maxSlew = rate
+	}
+}
+
+// NewMonotonicClock creates a MonotonicClock anchored at the current wall
+// time.
+func NewMonotonicClock(opts ...ClockOption) (*MonotonicClock, error) {
+	start := time.Now()
+	c := &MonotonicClock{
+		wall:      time.Now,
+		elapsed:   func() time.Duration { return time.Since(start) },
+		threshold: 100 * time.Millisecond,
+		maxSlew:   0.0005,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxSlew < 0 || c.maxSlew >= 1 {
+		return nil, &customerr.ConfigError{
+			Err: errors.New("maximum slew rate must be at least 0 and below 1"),
+			Op:  "timeflake:NewMonotonicClock",
+		}
+	}
+	// Round strips the monotonic reading, so the drift is computed from wall
+	// times.
+	c.anchor = c.wall().Round(0)
+	c.lastElapsed = c.elapsed()
+	c.offset = -c.lastElapsed
+	return c, nil
+}
+
+// Now returns the current time of the clock. It never decreases.
+func (c *MonotonicClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := c.elapsed()
+	step := elapsed - c.lastElapsed
+	if step < 0 {
+		step = 0
+		elapsed = c.lastElapsed
+	}
+	c.lastElapsed = elapsed
+
+	now := c.anchor.Add(elapsed + c.offset)
+	drift := c.wall().Round(0).Sub(now)
+	if drift > c.threshold || drift < -c.threshold {
+		c.slewing = true
+	}
+	if c.slewing {
+		limit := time.Duration(float64(step) * c.maxSlew)
+		adjust := drift
+		if adjust > limit {
+			adjust = limit
+		} else if adjust < -limit {
+			adjust = -limit
+		} else {
+			c.slewing = false
+		}
+		c.offset += adjust
+		now = now.Add(adjust)
+	}
+	return now
+}
+
+// Drift returns how far the wall clock is ahead of the clock.
+func (c *MonotonicClock) Drift() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wall().Round(0).Sub(c.anchor.Add(c.lastElapsed + c.offset))
+}
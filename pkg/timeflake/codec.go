@@ -0,0 +1,82 @@
+package timeflake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// Codec hides the creation time of Timeflakes by encrypting their 128 bits
+// with AES. A single AES block is a keyed permutation, so the opaque ID has
+// the same width and encodings as a Timeflake and decrypts back to it.
+//
+// Encrypt always uses the primary key. To rotate keys, make the new key
+// primary and pass the old keys as previous keys. The opaque ID does not
+// record its key and any key decrypts it to some value, so the key has to be
+// known: Decrypt uses the primary key, DecryptKey the given one. Store the key
+// index next to IDs that outlive a rotation.
+type Codec struct {
+	blocks []cipher.Block
+}
+
+// NewCodec creates a Codec from AES keys of 16, 24 or 32 Bytes.
+func NewCodec(primary []byte, previous ...[]byte) (*Codec, error) {
+	c := &Codec{}
+	for _, key := range append([][]byte{primary}, previous...) {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, &customerr.ConversionError{
+				Err: errors.New("key must be 16, 24 or 32 Bytes"),
+				Op:  "timeflake:NewCodec",
+			}
+		}
+		c.blocks = append(c.blocks, block)
+	}
+	return c, nil
+}
+
+// Encrypt returns the opaque ID of f.
+func (c *Codec) Encrypt(f *Timeflake) (*Timeflake, error) {
+	if len(f.Bytes) != 16 {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("timeflake must be 16 Bytes"),
+			Op:  "timeflake:Codec.Encrypt",
+		}
+	}
+	dst := make([]byte, 16)
+	c.blocks[0].Encrypt(dst, f.Bytes)
+	return FromBytes(dst)
+}
+
+// Decrypt returns the Timeflake of an opaque ID encrypted with the primary
+// key. It is DecryptKey with key 0.
+func (c *Codec) Decrypt(opaque *Timeflake) (*Timeflake, error) {
+	return c.decrypt(opaque, 0, "timeflake:Codec.Decrypt")
+}
+
+// DecryptKey returns the Timeflake of an opaque ID that was encrypted with
+// the given key: 0 is the primary key, 1 the first previous key and so on.
+func (c *Codec) DecryptKey(opaque *Timeflake, key int) (*Timeflake, error) {
+	const op = "timeflake:Codec.DecryptKey"
+	if key < 0 || key >= len(c.blocks) {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("key index out of range"),
+			Op:  op,
+		}
+	}
+	return c.decrypt(opaque, key, op)
+}
+
+func (c *Codec) decrypt(opaque *Timeflake, key int, op string) (*Timeflake, error) {
+	if len(opaque.Bytes) != 16 {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("opaque ID must be 16 Bytes"),
+			Op:  op,
+		}
+	}
+	dst := make([]byte, 16)
+	c.blocks[key].Decrypt(dst, opaque.Bytes)
+	return FromBytes(dst)
+}
@@ -0,0 +1,153 @@
+package timeflake
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// ErrEntropyClosed is returned by reads from a closed BufferedEntropy.
+var ErrEntropyClosed = errors.New("entropy source is closed")
+
+// BufferedEntropy is a random source that reads from crypto/rand in large
+// blocks and hands out small reads from them, so a Generator does not need a
+// system call per Timeflake. A background goroutine reads the next block
+// while the current one is used. Use it with WithEntropy and Close it when it
+// is no longer needed.
+//
+// BufferedEntropy is safe for concurrent use. Bytes are handed out once and
+// wiped from the buffer afterwards.
+type BufferedEntropy struct {
+	mu     sync.Mutex
+	buf    []byte
+	pos    int
+	gen    uint64
+	reseed chan uint64
+	blocks chan entropyBlock
+	done   chan struct{}
+	once   sync.Once
+}
+
+type entropyBlock struct {
+	data []byte
+	gen  uint64
+	err  error
+}
+
+// NewBufferedEntropy creates a BufferedEntropy that reads blocks of
+// blockSize Bytes from src, or from crypto/rand if src is nil.
+func NewBufferedEntropy(src io.Reader, blockSize int) *BufferedEntropy {
+	if src == nil {
+		src = rand.Reader
+	}
+	if blockSize < 16 {
+		blockSize = 16
+	}
+	b := &BufferedEntropy{
+		reseed: make(chan uint64, 1),
+		blocks: make(chan entropyBlock),
+		done:   make(chan struct{}),
+	}
+	go b.run(src, blockSize)
+	return b
+}
+
+func (b *BufferedEntropy) run(src io.Reader, blockSize int) {
+	var gen uint64
+	for {
+		select {
+		case gen = <-b.reseed:
+		default:
+		}
+		block := entropyBlock{data: make([]byte, blockSize), gen: gen}
+		_, block.err = io.ReadFull(src, block.data)
+		select {
+		case b.blocks <- block:
+		case gen = <-b.reseed:
+			// The block was read before the reseed, throw it away.
+			wipe(block.data)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Read fills p with random Bytes. It only blocks when the buffer is used up
+// and the next block is not read yet.
+func (b *BufferedEntropy) Read(p []byte) (int, error) {
+	const op = "timeflake:BufferedEntropy.Read"
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		if b.pos == len(b.buf) {
+			if err := b.next(); err != nil {
+				return n, &customerr.GeneratorError{
+					Err: err,
+					Op:  op,
+				}
+			}
+		}
+		c := copy(p[n:], b.buf[b.pos:])
+		wipe(b.buf[b.pos : b.pos+c])
+		b.pos += c
+		n += c
+	}
+	return n, nil
+}
+
+// next replaces the used up buffer by the next block.
+func (b *BufferedEntropy) next() error {
+	for {
+		select {
+		case block := <-b.blocks:
+			if block.gen != b.gen {
+				wipe(block.data)
+				continue
+			}
+			if block.err != nil {
+				return block.err
+			}
+			b.buf, b.pos = block.data, 0
+			return nil
+		case <-b.done:
+			return ErrEntropyClosed
+		}
+	}
+}
+
+// Reseed throws away the buffered Bytes. Reads after Reseed only get Bytes
+// that were read from the source after it was called.
+func (b *BufferedEntropy) Reseed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wipe(b.buf[b.pos:])
+	b.buf, b.pos = nil, 0
+	b.gen++
+	// Only the latest generation matters, replace a pending one.
+	select {
+	case <-b.reseed:
+	default:
+	}
+	b.reseed <- b.gen
+}
+
+// Close stops the background goroutine. Reads after Close fail once the
+// buffer is used up.
+func (b *BufferedEntropy) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	return nil
+}
+
+func wipe(p []byte) {
+	for i := range p {
+		p[i] = 0
+	}
+}
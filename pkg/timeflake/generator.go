@@ -0,0 +1,321 @@
+package timeflake
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// Generator creates strictly increasing Timeflakes and is safe for
+// concurrent use.
+//
+// Timeflakes of the same millisecond, or of the same fraction of it for
+// layouts with FractionBits, get the random part of their predecessor
+// incremented by one. If the clock goes backwards, the Generator keeps using
+// the last timestamp until the clock catches up. With WithClockWait it waits
+// for the clock instead.
+type Generator struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entropy io.Reader
+	v7      bool
+	wait    bool
+	layout  Layout
+	// The top shardBits of the random part hold shard, see
+	// ShardedGenerator.
+	shard     uint64
+	shardBits uint
+	// err is the error of an invalid configuration, found by
+	// NewGenerator and returned by every call.
+	err      error
+	lastMs   int64
+	lastFrac int64
+	last     *big.Int
+
+	store    StateStore
+	ahead    int64
+	loaded   bool
+	floor    int64
+	reserved int64
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithClock replaces time.Now as the source of timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(g *Generator) {
+		g.now = now
+	}
+}
+
+// WithEntropy replaces crypto/rand as the source of random parts.
+func WithEntropy(r io.Reader) Option {
+	return func(g *Generator) {
+		g.entropy = r
+	}
+}
+
+// WithUUIDv7 makes the Generator set the version and variant bits of a
+// RFC 9562 UUIDv7. This leaves 74 instead of 80 random bits.
+func WithUUIDv7() Option {
+	return func(g *Generator) {
+		g.v7 = true
+	}
+}
+
+// WithLayout makes the Generator create IDs of the Layout l instead of
+// DefaultLayout. IDs of a Layout other than DefaultLayout or SubMillisLayout
+// are no Timeflakes: get them with NextID and decode them with the methods of
+// l. It can not be combined with WithUUIDv7.
+func WithLayout(l Layout) Option {
+	return func(g *Generator) {
+		g.layout = l
+	}
+}
+
+// WithStateStore makes the Generator persist the highest timestamp it
+// issued in s. To not write on every Timeflake, it reserves ahead at a time:
+// the stored timestamp is that much ahead of the last issued one. On its first
+// use, the Generator loads the stored timestamp and only issues later ones, so
+// after a restart with the clock set back it continues above the Timeflakes
+// issued before. A restart without a clock change may skip up to ahead.
+func WithStateStore(s StateStore, ahead time.Duration) Option {
+	return func(g *Generator) {
+		g.store = s
+		g.ahead = int64(ahead / time.Millisecond)
+	}
+}
+
+// WithClockWait makes the Generator wait until its clock reaches the
+// millisecond of the next Timeflake, instead of issuing Timeflakes ahead of
+// the clock. That happens when the random part of a millisecond is exhausted,
+// when the clock went backwards and when the clock is behind a StateStore.
+// Use NextContext to bound the wait.
+func WithClockWait() Option {
+	return func(g *Generator) {
+		g.wait = true
+	}
+}
+
+// NewGenerator creates a Generator. An invalid configuration, like an
+// invalid Layout, is checked once and returned as ConfigError by every call
+// of the Generator.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{now: time.Now, entropy: rand.Reader, layout: DefaultLayout, lastMs: -1}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.err = g.check("timeflake:NewGenerator")
+	return g
+}
+
+func (g *Generator) check(op string) error {
+	if err := g.layout.validate(op); err != nil {
+		return err
+	}
+	if g.v7 && (!g.layout.isDefault() || g.layout.FractionBits > 0) {
+		return &customerr.ConfigError{
+			Err: errors.New("UUIDv7 needs the default layout"),
+			Op:  op,
+		}
+	}
+	return nil
+}
+
+// timeflakes returns an error unless the IDs of the Generator are
+// Timeflakes.
+func (g *Generator) timeflakes(op string) error {
+	if g.err != nil || g.layout.isDefault() {
+		return g.err
+	}
+	return &customerr.ConfigError{
+		Err: fmt.Errorf("IDs of layout %q are no Timeflakes, use NextID", g.layout.Name),
+		Op:  op,
+	}
+}
+
+// Next returns a Timeflake greater than every Timeflake returned before. It
+// is NextContext without a deadline.
+func (g *Generator) Next() (*Timeflake, error) {
+	return g.NextContext(context.Background())
+}
+
+// NextContext returns a Timeflake greater than every Timeflake returned
+// before. If the Generator has to wait for its clock, it gives up when ctx is
+// done and returns ctx.Err() wrapped in a GeneratorError. It fails for
+// Layouts whose IDs are no Timeflakes, see NextIDContext.
+func (g *Generator) NextContext(ctx context.Context) (*Timeflake, error) {
+	const op = "timeflake:Generator.NextContext"
+	if err := g.timeflakes(op); err != nil {
+		return nil, err
+	}
+	id, err := g.nextID(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	return id.Timeflake(), nil
+}
+
+// NextID returns an ID greater than every ID returned before. It is
+// NextIDContext without a deadline.
+func (g *Generator) NextID() (ID, error) {
+	return g.NextIDContext(context.Background())
+}
+
+// NextIDContext is NextContext for any Layout. The ID has to be decoded
+// with the methods of the Layout of the Generator.
+func (g *Generator) NextIDContext(ctx context.Context) (ID, error) {
+	return g.nextID(ctx, "timeflake:Generator.NextIDContext")
+}
+
+func (g *Generator) nextID(ctx context.Context, op string) (ID, error) {
+	var id ID
+	err := g.retry(ctx, op, func() (wait time.Duration, err error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		id, wait, err = g.next(op)
+		return wait, err
+	})
+	return id, err
+}
+
+// retry calls fn until it does not ask to wait for the clock anymore, or ctx
+// is done.
+func (g *Generator) retry(ctx context.Context, op string, fn func() (time.Duration, error)) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return &customerr.GeneratorError{
+				Err: err,
+				Op:  op,
+			}
+		}
+		wait, err := fn()
+		if wait <= 0 {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &customerr.GeneratorError{
+				Err: ctx.Err(),
+				Op:  op,
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+// next creates the next ID, or returns how long to wait for the clock before
+// trying again. g.mu must be held.
+func (g *Generator) next(op string) (ID, time.Duration, error) {
+	if g.err != nil {
+		return ID{}, 0, g.err
+	}
+
+	t := g.now()
+	ms, frac := g.layout.timestamp(t), g.layout.fraction(t)
+
+	if g.store != nil {
+		if !g.loaded {
+			stored, err := g.store.Load()
+			if err != nil {
+				return ID{}, 0, &customerr.GeneratorError{
+					Err: err,
+					Op:  op,
+				}
+			}
+			g.floor, g.reserved, g.loaded = stored, stored, true
+		}
+		if ms <= g.floor {
+			ms, frac = g.floor+1, 0
+		}
+	}
+
+	bits := int(g.randomBits() - g.shardBits)
+
+	var random *big.Int
+	if g.last != nil && (ms < g.lastMs || ms == g.lastMs && frac <= g.lastFrac) {
+		ms, frac = g.lastMs, g.lastFrac
+		random = new(big.Int).Add(g.last, big.NewInt(1))
+		if random.BitLen() > bits {
+			// The random part of this fraction is exhausted, borrow
+			// the next one.
+			frac++
+			if frac == 1<<g.layout.FractionBits {
+				ms, frac = ms+1, 0
+			}
+			random = nil
+		}
+	}
+
+	if clock := g.layout.timestamp(t); g.wait && ms > clock {
+		return ID{}, time.Duration(ms-clock) * time.Millisecond, nil
+	}
+
+	if random == nil {
+		p := make([]byte, (bits+7)/8)
+		if _, err := io.ReadFull(g.entropy, p); err != nil {
+			return ID{}, 0, &customerr.GeneratorError{
+				Err: err,
+				Op:  op,
+			}
+		}
+		random = new(big.Int).SetBytes(p)
+		random.Rsh(random, uint(len(p)*8-bits))
+	}
+
+	packed := random
+	if g.shardBits > 0 {
+		packed = new(big.Int).Lsh(new(big.Int).SetUint64(g.shard), uint(bits))
+		packed.Or(packed, random)
+	}
+	if g.v7 {
+		packed = packUUIDv7(packed)
+	}
+	id, err := g.layout.pack(ms, frac, packed, op)
+	if err != nil {
+		return ID{}, 0, err
+	}
+	if g.store != nil && ms > g.reserved {
+		if err := g.store.Reserve(ms + g.ahead); err != nil {
+			return ID{}, 0, &customerr.GeneratorError{
+				Err: err,
+				Op:  op,
+			}
+		}
+		g.reserved = ms + g.ahead
+	}
+	g.lastMs, g.lastFrac = ms, frac
+	g.last = random
+	return id, 0, nil
+}
+
+// NextN returns n increasing Timeflakes. It is NextNContext without a
+// deadline.
+func (g *Generator) NextN(n int) ([]*Timeflake, error) {
+	return g.NextNContext(context.Background(), n)
+}
+
+// NextNContext returns n increasing Timeflakes, or the error of the first
+// NextContext that fails.
+func (g *Generator) NextNContext(ctx context.Context, n int) ([]*Timeflake, error) {
+	flakes := make([]*Timeflake, 0, n)
+	for i := 0; i < n; i++ {
+		f, err := g.NextContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		flakes = append(flakes, f)
+	}
+	return flakes, nil
+}
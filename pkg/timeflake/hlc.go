@@ -0,0 +1,77 @@
+package timeflake
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// HLC is a Generator that follows the hybrid logical clock algorithm. Next
+// returns Timeflakes greater than every ID the HLC issued or observed, so an
+// event gets an ID greater than the IDs it causally depends on, even if the
+// clocks of the nodes are skewed.
+//
+// The logical time is the highest timestamp seen. It follows the local clock
+// and moves forward when Observe sees an ID from the future. Observe rejects
+// IDs more than the maximum drift ahead of the local clock, so a node with a
+// broken clock can not drag the others along.
+type HLC struct {
+	*Generator
+	maxDrift int64
+}
+
+// NewHLC creates a HLC that refuses to move more than maxDrift ahead of its
+// clock.
+func NewHLC(maxDrift time.Duration, opts ...Option) *HLC {
+	return &HLC{
+		Generator: NewGenerator(opts...),
+		maxDrift:  int64(maxDrift / time.Millisecond),
+	}
+}
+
+// Observe moves the logical time of the HLC forward to id, if id is greater
+// than every ID issued or observed before. It returns an OutOfBoundsError if
+// id is more than the maximum drift ahead of the clock. id must have the
+// Layout of the HLC. With WithUUIDv7, IDs that are no UUIDv7s are observed
+// as the end of their millisecond.
+func (h *HLC) Observe(id ID) error {
+	const op = "timeflake:HLC.Observe"
+	g := h.Generator
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	if uint(id.Int().BitLen()) > g.layout.Width() {
+		return &customerr.OutOfBoundsError{
+			Err: errors.New("ID does not belong to the layout of the HLC"),
+			Op:  op,
+		}
+	}
+
+	ms, frac, random := g.layout.split(id)
+	if ahead := ms - g.layout.timestamp(g.now()); ahead > h.maxDrift {
+		return &customerr.OutOfBoundsError{
+			Err: fmt.Errorf("ID is %dms ahead of the clock, more than the maximum drift of %dms", ahead, h.maxDrift),
+			Op:  op,
+		}
+	}
+	if g.v7 && id[6]>>4 == 7 && id[8]>>6 == 2 {
+		random = unpackUUIDv7(random)
+	} else if g.v7 {
+		// Unpacking would drop the bits at the version and variant
+		// positions, and the next UUIDv7 of the millisecond could be
+		// lower than id. Exhaust the millisecond instead, so the next ID
+		// continues in the following one.
+		random = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), v7RandomBits), big.NewInt(1))
+	}
+
+	if g.last == nil || ms > g.lastMs || ms == g.lastMs && (frac > g.lastFrac || frac == g.lastFrac && random.Cmp(g.last) > 0) {
+		g.lastMs, g.lastFrac, g.last = ms, frac, random
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package timeflake
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/internal/alphabets"
+	"github.com/gioni06/go-timeflake/internal/utils"
+)
+
+// ID is an immutable Timeflake. Unlike the fields of Timeflake, its
+// encodings are computed from the 16 Bytes when they are asked for, so they
+// can never disagree. ID is comparable and can be used as map key.
+//
+// ID implements fmt.Formatter: %s and %v print base62, %q a quoted base62,
+// %x and %X hex, %d the integer and %+v a breakdown of all components.
+type ID [16]byte
+
+// ID returns the value of the Timeflake.
+func (f *Timeflake) ID() ID {
+	var id ID
+	copy(id[:], f.Bytes)
+	return id
+}
+
+// Timeflake returns the ID as Timeflake, with all encodings computed.
+func (id ID) Timeflake() *Timeflake {
+	// FromBytes only fails for slices that are not 16 Bytes.
+	f, _ := FromBytes(id[:])
+	return f
+}
+
+// String returns the ID in base62.
+func (id ID) String() string {
+	return id.Base62()
+}
+
+// Base62 returns the 22 character base62 encoding.
+func (id ID) Base62() string {
+	s, _ := utils.BigIntToASCII(id.Int(), alphabets.BASE62, 22)
+	return s
+}
+
+// Hex returns the 32 character lower case hex encoding.
+func (id ID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+// UUIDString returns the ID formatted as UUID.
+func (id ID) UUIDString() string {
+	return uuid.UUID(id).String()
+}
+
+// Bytes returns a copy of the 16 Bytes.
+func (id ID) Bytes() []byte {
+	b := make([]byte, 16)
+	copy(b, id[:])
+	return b
+}
+
+// Int returns the ID as integer.
+func (id ID) Int() *big.Int {
+	return new(big.Int).SetBytes(id[:])
+}
+
+// Random returns the 80 bit random part.
+func (id ID) Random() *big.Int {
+	return new(big.Int).SetBytes(id[6:])
+}
+
+// Time returns the creation time of the ID with millisecond precision.
+func (id ID) Time() time.Time {
+	ms := id.unixMilli()
+	return time.UnixMilli(ms).UTC()
+}
+
+func (id ID) unixMilli() int64 {
+	return int64(id[0])<<40 | int64(id[1])<<32 | int64(id[2])<<24 | int64(id[3])<<16 | int64(id[4])<<8 | int64(id[5])
+}
+
+// Format implements fmt.Formatter.
+func (id ID) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "ts=%d rand=%s int=%s hex=%s base62=%s uuid=%s",
+				id.unixMilli(),
+				id.Random().String(),
+				id.Int().String(),
+				id.Hex(),
+				id.Base62(),
+				id.UUIDString(),
+			)
+			return
+		}
+		fmt.Fprint(s, id.Base62())
+	case 's':
+		fmt.Fprint(s, id.Base62())
+	case 'q':
+		fmt.Fprintf(s, "%q", id.Base62())
+	case 'x':
+		fmt.Fprint(s, id.Hex())
+	case 'X':
+		fmt.Fprint(s, strings.ToUpper(id.Hex()))
+	case 'd':
+		fmt.Fprint(s, id.Int().String())
+	default:
+		fmt.Fprintf(s, "%%!%c(timeflake.ID=%s)", verb, id.Base62())
+	}
+}
+
+// ParseID parses an ID in any of the Formats.
+func ParseID(value string) (ID, error) {
+	f, err := Parse(value)
+	if err != nil {
+		return ID{}, err
+	}
+	return f.ID(), nil
+}
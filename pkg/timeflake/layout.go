@@ -0,0 +1,279 @@
+package timeflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/alphabets"
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/internal/utils"
+)
+
+// Layout describes how the timestamp and the random part are packed into an
+// ID. The timestamp counts milliseconds since Epoch, so a recent Epoch needs
+// fewer TimestampBits for the same lifetime, or extends the lifetime of the
+// default 48 bits.
+//
+// IDs of a Layout are kept in the low bits of an ID, with the timestamp in
+// front of the random part. They have to be created by a Generator with the
+// same Layout and decoded with its methods, the time of an ID only makes
+// sense together with the Epoch.
+//
+// With FractionBits, the top bits of the random part hold the fraction of
+// the millisecond the ID was created in, like the rand_a field of method 3
+// UUIDv7s. IDs then sort by creation time down to the resolution of the
+// fraction, not only by millisecond.
+//
+// Encoded IDs of a named Layout are prefixed with its Name and a fingerprint
+// of its Epoch and bit widths, so they are not mixed up with IDs of another
+// Layout by accident, even one that has the same Name.
+type Layout struct {
+	// Name is the prefix of encoded IDs. It must be set for every Layout
+	// but DefaultLayout.
+	Name string
+	// Epoch is the time of timestamp 0.
+	Epoch time.Time
+	// TimestampBits is the width of the timestamp, at most 48.
+	TimestampBits uint
+	// RandomBits is the width of the random part, at most 128 minus
+	// TimestampBits.
+	RandomBits uint
+	// FractionBits is the number of top bits of the random part that hold
+	// the sub-millisecond fraction, at most 20.
+	FractionBits uint
+}
+
+// DefaultLayout is the layout of Timeflakes: 48 bits of milliseconds since
+// the Unix epoch, followed by 80 random bits.
+var DefaultLayout = Layout{
+	Epoch:         time.Unix(0, 0).UTC(),
+	TimestampBits: 48,
+	RandomBits:    80,
+}
+
+// SubMillisLayout is DefaultLayout with a 12 bit sub-millisecond fraction,
+// a resolution of about 244ns. Its IDs are ordinary Timeflakes and do not
+// need a Name.
+var SubMillisLayout = Layout{
+	Epoch:         time.Unix(0, 0).UTC(),
+	TimestampBits: 48,
+	RandomBits:    80,
+	FractionBits:  12,
+}
+
+// isDefault reports whether IDs of the Layout are Timeflakes. The fraction
+// does not matter for that.
+func (l Layout) isDefault() bool {
+	return l.Name == "" && l.Epoch.Equal(DefaultLayout.Epoch) &&
+		l.TimestampBits == DefaultLayout.TimestampBits && l.RandomBits == DefaultLayout.RandomBits
+}
+
+func (l Layout) validate(op string) error {
+	var msg string
+	switch {
+	case l.TimestampBits == 0 || l.TimestampBits > 48:
+		msg = "timestamp bits must be between 1 and 48"
+	case l.RandomBits == 0 || l.TimestampBits+l.RandomBits > 128:
+		msg = "random bits must be between 1 and 128 minus the timestamp bits"
+	case l.FractionBits > 20 || l.FractionBits >= l.RandomBits:
+		msg = "fraction bits must be at most 20 and less than the random bits"
+	case l.Name == "" && !l.isDefault():
+		msg = "a custom layout needs a name"
+	case strings.ContainsAny(l.Name, ": \t\r\n"):
+		msg = "the layout name must not contain colons or white space"
+	default:
+		return nil
+	}
+	return &customerr.ConfigError{
+		Err: errors.New(msg),
+		Op:  op,
+	}
+}
+
+// Width returns the number of bits of an ID.
+func (l Layout) Width() uint {
+	return l.TimestampBits + l.RandomBits
+}
+
+func (l Layout) epochMilli() int64 {
+	return l.Epoch.UnixMilli()
+}
+
+// timestamp returns the milliseconds between Epoch and t.
+func (l Layout) timestamp(t time.Time) int64 {
+	return t.UnixMilli() - l.epochMilli()
+}
+
+// fraction returns the sub-millisecond part of t in FractionBits.
+func (l Layout) fraction(t time.Time) int64 {
+	return int64(t.Nanosecond()) % int64(time.Millisecond) << l.FractionBits / int64(time.Millisecond)
+}
+
+// pack combines a timestamp, the fraction and a random part into an ID.
+func (l Layout) pack(ts, frac int64, random *big.Int, op string) (ID, error) {
+	var id ID
+	if ts < 0 || ts >= int64(1)<<l.TimestampBits {
+		return id, &customerr.OutOfBoundsError{
+			Err: errors.New("time is outside of the layout range"),
+			Op:  op,
+		}
+	}
+	bits := l.RandomBits - l.FractionBits
+	if random.Sign() < 0 || uint(random.BitLen()) > bits {
+		return id, &customerr.OutOfBoundsError{
+			Err: fmt.Errorf("random part must fit into %d bits", bits),
+			Op:  op,
+		}
+	}
+	v := new(big.Int).Lsh(big.NewInt(ts), l.FractionBits)
+	v.Or(v, big.NewInt(frac))
+	v.Lsh(v, bits)
+	v.Or(v, random)
+	v.FillBytes(id[:])
+	return id, nil
+}
+
+// Time returns the creation time of an ID of the Layout, with the
+// resolution of the fraction if the Layout has one.
+func (l Layout) Time(id ID) time.Time {
+	ts, frac, _ := l.split(id)
+	ms := l.epochMilli() + ts
+	ns := ms%1000*int64(time.Millisecond) + frac*int64(time.Millisecond)>>l.FractionBits
+	return time.Unix(ms/1000, ns).UTC()
+}
+
+// split returns the timestamp, the fraction and the random part of an ID.
+func (l Layout) split(id ID) (int64, int64, *big.Int) {
+	v := new(big.Int).Rsh(id.Int(), l.RandomBits-l.FractionBits)
+	frac := new(big.Int).And(v, big.NewInt(1<<l.FractionBits-1)).Int64()
+	return v.Rsh(v, l.FractionBits).Int64(), frac, l.Random(id)
+}
+
+// Random returns the random part of an ID of the Layout, without the
+// fraction.
+func (l Layout) Random(id ID) *big.Int {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), l.RandomBits-l.FractionBits), big.NewInt(1))
+	return mask.And(mask, id.Int())
+}
+
+// ForTime returns an ID of the Layout for t and random. random must fit into
+// RandomBits minus FractionBits.
+func (l Layout) ForTime(t time.Time, random *big.Int) (ID, error) {
+	const op = "timeflake:Layout.ForTime"
+	if err := l.validate(op); err != nil {
+		return ID{}, err
+	}
+	return l.pack(l.timestamp(t), l.fraction(t), random, op)
+}
+
+// base62Length returns the number of base62 characters of an encoded ID.
+func (l Layout) base62Length() int {
+	return int(math.Ceil(float64(l.Width()) / math.Log2(62)))
+}
+
+// prefix returns the Name and a fingerprint of the Epoch and the bit
+// widths, separated by a dot.
+func (l Layout) prefix() string {
+	h := fnv.New32a()
+	var b [15]byte
+	binary.BigEndian.PutUint64(b[:], uint64(l.Epoch.Unix()))
+	binary.BigEndian.PutUint32(b[8:], uint32(l.Epoch.Nanosecond()))
+	b[12], b[13], b[14] = byte(l.TimestampBits), byte(l.RandomBits), byte(l.FractionBits)
+	h.Write(b[:])
+	fingerprint, _ := utils.BigIntToASCII(new(big.Int).SetUint64(uint64(h.Sum32())%(62*62*62*62)), alphabets.BASE62, 4)
+	return l.Name + "." + fingerprint
+}
+
+// Encode returns the ID in base62, padded to the width of the Layout and
+// prefixed with the Name, a dot, a fingerprint of the Layout and a colon.
+// For DefaultLayout this is the usual 22 character base62 encoding.
+func (l Layout) Encode(id ID) string {
+	s, _ := utils.BigIntToASCII(id.Int(), alphabets.BASE62, l.base62Length())
+	if l.Name == "" {
+		return s
+	}
+	return l.prefix() + ":" + s
+}
+
+// Parse parses an ID encoded by Encode. IDs of another Layout are rejected.
+// DefaultLayout accepts all of the Formats, like ParseID.
+func (l Layout) Parse(value string) (ID, error) {
+	const op = "timeflake:Layout.Parse"
+	if err := l.validate(op); err != nil {
+		return ID{}, err
+	}
+	if l.Name == "" {
+		if strings.Contains(value, ":") {
+			return ID{}, &customerr.ConversionError{
+				Err: errors.New("ID belongs to a named layout"),
+				Op:  op,
+			}
+		}
+		return ParseID(value)
+	}
+
+	prefix, encoded, ok := strings.Cut(value, ":")
+	if !ok || prefix != l.prefix() {
+		msg := "ID does not belong to layout %q"
+		if strings.HasPrefix(prefix, l.Name+".") {
+			msg = "ID belongs to another layout named %q"
+		}
+		return ID{}, &customerr.ConversionError{
+			Err: fmt.Errorf(msg, l.Name),
+			Op:  op,
+		}
+	}
+	if len(encoded) != l.base62Length() {
+		return ID{}, &customerr.ConversionError{
+			Err: fmt.Errorf("ID of layout %q must be %d characters", l.Name, l.base62Length()),
+			Op:  op,
+		}
+	}
+	f, err := decodeAlphabet(encoded, alphabets.BASE62, op)
+	if err != nil {
+		return ID{}, err
+	}
+	id := f.ID()
+	if uint(id.Int().BitLen()) > l.Width() {
+		return ID{}, &customerr.OutOfBoundsError{
+			Err: fmt.Errorf("ID does not fit into %d bits", l.Width()),
+			Op:  op,
+		}
+	}
+	return id, nil
+}
+
+// Bytes returns the ID in as few bytes as the width of the Layout allows,
+// 12 for a 96 bit Layout.
+func (l Layout) Bytes(id ID) []byte {
+	n := int(l.Width()+7) / 8
+	b := make([]byte, n)
+	copy(b, id[16-n:])
+	return b
+}
+
+// FromBytes is the inverse of Bytes.
+func (l Layout) FromBytes(b []byte) (ID, error) {
+	const op = "timeflake:Layout.FromBytes"
+	var id ID
+	if len(b) != int(l.Width()+7)/8 {
+		return id, &customerr.OutOfBoundsError{
+			Err: fmt.Errorf("layout %q needs %d Bytes", l.Name, int(l.Width()+7)/8),
+			Op:  op,
+		}
+	}
+	copy(id[16-len(b):], b)
+	if uint(id.Int().BitLen()) > l.Width() {
+		return ID{}, &customerr.OutOfBoundsError{
+			Err: fmt.Errorf("ID does not fit into %d bits", l.Width()),
+			Op:  op,
+		}
+	}
+	return id, nil
+}
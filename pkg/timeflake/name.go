@@ -0,0 +1,24 @@
+package timeflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+	"time"
+)
+
+// FromName creates a deterministic Timeflake, similar to a UUIDv5. The
+// timestamp is taken from t, the random part is filled with the first 80 bits
+// of an HMAC-SHA256 of name keyed with namespace. Replaying the same event
+// therefore always results in the same ID.
+//
+// Timeflakes of different milliseconds never collide. Within a millisecond and
+// namespace, the chance of a collision among n different names is about
+// n²/2^81, the same as for random Timeflakes. Without the namespace, the
+// random part of a name can not be predicted.
+func FromName(namespace, name []byte, t time.Time) (*Timeflake, error) {
+	mac := hmac.New(sha256.New, namespace)
+	mac.Write(name)
+	random := new(big.Int).SetBytes(mac.Sum(nil)[:10])
+	return forTime(t, random, "timeflake:FromName")
+}
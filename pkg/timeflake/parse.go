@@ -0,0 +1,95 @@
+package timeflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/internal/alphabets"
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/internal/utils"
+)
+
+// Format describes a textual representation of a Timeflake.
+type Format struct {
+	Name string
+	// Length of the representation, 0 if it varies.
+	Length int
+	Decode func(value string) (*Timeflake, error)
+	Encode func(f *Timeflake) string
+}
+
+// Formats lists the representations understood by Parse in the order they
+// are tried. The encoders only read the Bytes of a Timeflake.
+var Formats = []Format{
+	{Name: "base62", Length: 22, Decode: decodeBase62, Encode: func(f *Timeflake) string { return f.ID().Base62() }},
+	{Name: "hex", Length: 32, Decode: decodeHex, Encode: func(f *Timeflake) string { return f.ID().Hex() }},
+	{Name: "ulid", Length: 26, Decode: FromULIDString, Encode: (*Timeflake).ToULIDString},
+	{Name: "uuid", Length: 36, Decode: decodeUUID, Encode: func(f *Timeflake) string { return f.ID().UUIDString() }},
+	{Name: "int", Length: 0, Decode: decodeInt, Encode: func(f *Timeflake) string { return f.ID().Int().String() }},
+}
+
+// LookupFormat returns the entry of Formats with the given name.
+func LookupFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Format{}, &customerr.ConversionError{
+		Err: fmt.Errorf("unknown format '%s'", name),
+		Op:  "timeflake:LookupFormat",
+	}
+}
+
+// Parse creates a Timeflake from any of its textual representations. The
+// format is detected by the length of the value, so a decimal int with the
+// length of another format is not decoded as int. Use the Decode function of
+// the "int" Format for those.
+func Parse(value string) (*Timeflake, error) {
+	for _, f := range Formats {
+		if f.Length == 0 || f.Length == len(value) {
+			return f.Decode(value)
+		}
+	}
+	return nil, &customerr.ConversionError{
+		Err: fmt.Errorf("'%s' is not a timeflake", value),
+		Op:  "timeflake:Parse",
+	}
+}
+
+func decodeBase62(value string) (*Timeflake, error) {
+	return decodeAlphabet(value, alphabets.BASE62, "timeflake:decodeBase62")
+}
+
+// decodeHex accepts upper case hex as well, like %X of an ID prints.
+func decodeHex(value string) (*Timeflake, error) {
+	return decodeAlphabet(strings.ToLower(value), alphabets.HEX, "timeflake:decodeHex")
+}
+
+func decodeInt(value string) (*Timeflake, error) {
+	return decodeAlphabet(value, "0123456789", "timeflake:decodeInt")
+}
+
+func decodeAlphabet(value string, alphabet string, op string) (*Timeflake, error) {
+	if value == "" || strings.IndexFunc(value, func(r rune) bool { return !strings.ContainsRune(alphabet, r) }) >= 0 {
+		return nil, &customerr.ConversionError{
+			Err: fmt.Errorf("'%s' contains invalid characters", value),
+			Op:  op,
+		}
+	}
+	return fromInt(utils.ASCIIToBigInt(value, alphabet))
+}
+
+func decodeUUID(value string) (*Timeflake, error) {
+	u, err := uuid.Parse(value)
+	if err != nil {
+		return nil, &customerr.UUIDError{
+			Err: errors.New("invalid UUID"),
+			Op:  "timeflake:decodeUUID",
+		}
+	}
+	return FromBytes(u[:])
+}
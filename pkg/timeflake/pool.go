@@ -0,0 +1,190 @@
+package timeflake
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pool hands out Timeflakes that a background goroutine generated ahead of
+// time, so the hot path only receives from a channel. When fewer than the low
+// watermark are left, the pool is refilled up to the high watermark. When it
+// is empty, Next falls back to the Generator.
+//
+// Pooled Timeflakes carry the time they were generated at, not the time they
+// are handed out. Timeflakes older than the maximum lag are dropped, so the
+// timestamp of a Timeflake from Next is at most that far in the past. Because
+// of the fallback, Timeflakes from Next are unique but not always
+// increasing.
+type Pool struct {
+	gen    *Generator
+	ids    chan pooled
+	low    int
+	high   int
+	maxLag time.Duration
+	refill chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type pooled struct {
+	f       *Timeflake
+	created time.Time
+}
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithWatermarks sets the number of pooled Timeflakes below which the pool
+// is refilled and up to which it is refilled. The defaults are 256 and 1024.
+func WithWatermarks(low, high int) PoolOption {
+	return func(p *Pool) {
+		p.low, p.high = low, high
+	}
+}
+
+// WithMaxLag sets how old a pooled Timeflake may get before it is dropped.
+// The default is one second.
+func WithMaxLag(d time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.maxLag = d
+	}
+}
+
+// NewPool creates a Pool that takes Timeflakes from gen and starts refilling
+// it. The pool stops refilling when ctx is done or Close is called. The age of
+// pooled Timeflakes is measured with the clock of gen.
+func NewPool(ctx context.Context, gen *Generator, opts ...PoolOption) *Pool {
+	p := &Pool{
+		gen:    gen,
+		low:    256,
+		high:   1024,
+		maxLag: time.Second,
+		refill: make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.high < 1 {
+		p.high = 1
+	}
+	if p.low > p.high {
+		p.low = p.high
+	}
+	p.ids = make(chan pooled, p.high)
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(1)
+	go p.run(ctx)
+	p.signal()
+	return p
+}
+
+// Next returns a pooled Timeflake, or a new one from the Generator if the
+// pool is empty. It is NextContext without a deadline.
+func (p *Pool) Next() (*Timeflake, error) {
+	return p.NextContext(context.Background())
+}
+
+// NextContext returns a pooled Timeflake, or a new one from the NextContext
+// method of the Generator if the pool is empty.
+func (p *Pool) NextContext(ctx context.Context) (*Timeflake, error) {
+	for {
+		select {
+		case id := <-p.ids:
+			if len(p.ids) < p.low {
+				p.signal()
+			}
+			if p.stale(id) {
+				continue
+			}
+			return id.f, nil
+		default:
+			p.signal()
+			return p.gen.NextContext(ctx)
+		}
+	}
+}
+
+// Len returns the number of pooled Timeflakes.
+func (p *Pool) Len() int {
+	return len(p.ids)
+}
+
+// Close stops refilling and waits for the background goroutine to exit.
+// Next keeps working, without the pool.
+func (p *Pool) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Pool) stale(id pooled) bool {
+	return p.gen.now().Sub(id.created) > p.maxLag
+}
+
+func (p *Pool) signal() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool) run(ctx context.Context) {
+	defer p.wg.Done()
+
+	// Pooled Timeflakes age even if nobody asks for them, so the pool is
+	// checked for stale ones regularly.
+	interval := p.maxLag / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.refill:
+		case <-ticker.C:
+			p.dropStale()
+		}
+		p.fill(ctx)
+	}
+}
+
+// dropStale removes stale Timeflakes from the front of the pool. They are
+// the oldest, so it stops at the first fresh one, which is put back. That
+// moves it behind newer ones, which only matters for its order.
+func (p *Pool) dropStale() {
+	for {
+		select {
+		case id := <-p.ids:
+			if !p.stale(id) {
+				select {
+				case p.ids <- id:
+				default:
+				}
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *Pool) fill(ctx context.Context) {
+	for len(p.ids) < p.high {
+		created := p.gen.now()
+		f, err := p.gen.NextContext(ctx)
+		if err != nil {
+			// Next reports the error when it falls back to the Generator.
+			return
+		}
+		select {
+		case p.ids <- pooled{f: f, created: created}:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package timeflake
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// Time returns the creation time of the Timeflake with millisecond precision.
+func (f *Timeflake) Time() time.Time {
+	ms := new(big.Int)
+	ms.Rsh(&f.Int, 80)
+	return time.UnixMilli(ms.Int64()).UTC()
+}
+
+// MinForTime returns the smallest Timeflake that can be created within the
+// millisecond of t. Together with MaxForTime it is useful to query
+// ID-keyed tables by time.
+func MinForTime(t time.Time) (*Timeflake, error) {
+	return forTime(t, new(big.Int), "timeflake:MinForTime")
+}
+
+// MaxForTime returns the largest Timeflake that can be created within the
+// millisecond of t.
+func MaxForTime(t time.Time) (*Timeflake, error) {
+	r := new(big.Int)
+	r.SetString(maxRandom, 10)
+	return forTime(t, r, "timeflake:MaxForTime")
+}
+
+// RangeFor returns the smallest and the largest Timeflake of the window
+// [from, to), to query ID-keyed tables by time.
+func RangeFor(from, to time.Time) (min, max *Timeflake, err error) {
+	const op = "timeflake:RangeFor"
+	if !from.Before(to) {
+		return nil, nil, &customerr.OutOfBoundsError{
+			Err: errors.New("from must be before to"),
+			Op:  op,
+		}
+	}
+	if min, err = forTime(from, new(big.Int), op); err != nil {
+		return nil, nil, err
+	}
+	random, _ := new(big.Int).SetString(maxRandom, 10)
+	if max, err = forTime(to.Add(-time.Millisecond), random, op); err != nil {
+		return nil, nil, err
+	}
+	return min, max, nil
+}
+
+func forTime(t time.Time, random *big.Int, op string) (*Timeflake, error) {
+	ms := t.UnixMilli()
+	if ms < 0 || big.NewInt(ms).Cmp(MaxTimestamp()) > 0 {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("time is outside of the timeflake range"),
+			Op:  op,
+		}
+	}
+
+	v := new(big.Int)
+	v.Lsh(big.NewInt(ms), 80)
+	v.Or(v, random)
+	return fromInt(v)
+}
+
+// fromInt creates a Timeflake from its integer value. Unlike the Bytes method
+// of big.Int it keeps leading zero bytes, so small values are accepted as well.
+func fromInt(v *big.Int) (*Timeflake, error) {
+	if v.Sign() < 0 || v.Cmp(MaxTimeflake()) > 0 {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("value does not fit into 128 bits"),
+			Op:  "timeflake:fromInt",
+		}
+	}
+	return FromBytes(v.FillBytes(make([]byte, 16)))
+}
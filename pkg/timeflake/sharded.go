@@ -0,0 +1,131 @@
+package timeflake
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// MaxShards limits the number of shards of a ShardedGenerator.
+const MaxShards = 1024
+
+// ShardedGenerator spreads the work of a Generator over several shards,
+// each a Generator with its own mutex, to reduce the contention on a single
+// mutex. Next picks a start shard with an atomic counter shared by all calls
+// and takes the first shard whose mutex it can lock without waiting. Only if
+// all shards are busy it waits for the start shard. This reduces contention
+// but does not remove it: every call still updates the shared counter, and
+// with more concurrent callers than shards goroutines wait for each other.
+// Compare BenchmarkGeneratorParallel and BenchmarkShardedGeneratorParallel
+// with -cpu for the workload at hand.
+//
+// Ordering guarantee: the Timeflakes of one shard are strictly increasing.
+// Across shards, Timeflakes are only roughly ordered globally. Each shard
+// keeps its own state, so within a millisecond a Timeflake may be lower than
+// one issued before by another shard. A shard can also run ahead of the
+// others by whole milliseconds: a shard that exhausted the random part of a
+// millisecond continues in the next one, and a shard that loaded a
+// WithStateStore floor above the clock issues Timeflakes above that floor.
+// Timeflakes the other shards issue later can then be lower. Workers that
+// need strict order can use a shard of their own, see Shard.
+//
+// The top bits of the random part hold the index of the shard, so Timeflakes
+// of different shards never collide. With n shards, bits.Len(n-1) fewer bits
+// are random.
+type ShardedGenerator struct {
+	// next is accessed atomically and kept first for 64 bit alignment.
+	next   uint64
+	shards []*Generator
+}
+
+// NewShardedGenerator creates a ShardedGenerator with n shards, or with
+// GOMAXPROCS shards if n is 0. The options apply to every shard, so a
+// WithEntropy reader and a WithStateStore store must be safe for concurrent
+// use.
+func NewShardedGenerator(n int, opts ...Option) (*ShardedGenerator, error) {
+	const op = "timeflake:NewShardedGenerator"
+	if n == 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	if n < 1 || n > MaxShards {
+		return nil, &customerr.ConfigError{
+			Err: errors.New("number of shards must be between 1 and 1024"),
+			Op:  op,
+		}
+	}
+	s := &ShardedGenerator{shards: make([]*Generator, n)}
+	shardBits := uint(bits.Len(uint(n - 1)))
+	for i := range s.shards {
+		g := NewGenerator(opts...)
+		if g.err != nil {
+			return nil, g.err
+		}
+		if g.randomBits() <= shardBits {
+			return nil, &customerr.ConfigError{
+				Err: errors.New("no random bits are left next to the shard"),
+				Op:  op,
+			}
+		}
+		g.shard, g.shardBits = uint64(i), shardBits
+		s.shards[i] = g
+	}
+	return s, nil
+}
+
+// Len returns the number of shards.
+func (s *ShardedGenerator) Len() int {
+	return len(s.shards)
+}
+
+// Shard returns the shard i, which can be used like any Generator. Its
+// Timeflakes are strictly increasing, also when other goroutines use it or
+// the ShardedGenerator at the same time.
+func (s *ShardedGenerator) Shard(i int) *Generator {
+	return s.shards[i]
+}
+
+// Next returns a Timeflake of one of the shards. It is NextContext without a
+// deadline.
+func (s *ShardedGenerator) Next() (*Timeflake, error) {
+	return s.NextContext(context.Background())
+}
+
+// NextContext returns a Timeflake of one of the shards, see the NextContext
+// method of Generator. For Layouts whose IDs are no Timeflakes, use the
+// NextIDContext method of a shard.
+func (s *ShardedGenerator) NextContext(ctx context.Context) (*Timeflake, error) {
+	const op = "timeflake:ShardedGenerator.NextContext"
+	if err := s.shards[0].timeflakes(op); err != nil {
+		return nil, err
+	}
+	var id ID
+	err := s.shards[0].retry(ctx, op, func() (wait time.Duration, err error) {
+		g := s.acquire()
+		defer g.mu.Unlock()
+		id, wait, err = g.next(op)
+		return wait, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return id.Timeflake(), nil
+}
+
+// acquire returns a locked shard.
+func (s *ShardedGenerator) acquire() *Generator {
+	n := uint64(len(s.shards))
+	start := atomic.AddUint64(&s.next, 1)
+	for i := uint64(0); i < n; i++ {
+		if g := s.shards[(start+i)%n]; g.mu.TryLock() {
+			return g
+		}
+	}
+	g := s.shards[start%n]
+	g.mu.Lock()
+	return g
+}
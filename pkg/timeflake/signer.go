@@ -0,0 +1,98 @@
+package timeflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+var (
+	// ErrMalformedToken is wrapped by Verify if the token can not be split
+	// into ID and tag.
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrInvalidSignature is wrapped by Verify if no key produces the tag.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signer appends a truncated HMAC-SHA256 tag to the base62 form of a
+// Timeflake, like "02lVIoVLUfN6xUwLlnSRjj.3q2-7w". Forged or guessed IDs can
+// be rejected before they hit the database.
+//
+// Sign always uses the primary key, Verify accepts tags of every key, so keys
+// can be rotated by making the new key primary and keeping the old one as
+// previous key until its tokens expire.
+type Signer struct {
+	keys      [][]byte
+	tagLength int
+}
+
+// NewSigner creates a Signer with tags of tagLength Bytes, between 4 and 32.
+func NewSigner(tagLength int, primary []byte, previous ...[]byte) (*Signer, error) {
+	const op = "timeflake:NewSigner"
+	if tagLength < 4 || tagLength > sha256.Size {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("tag length must be between 4 and 32 Bytes"),
+			Op:  op,
+		}
+	}
+	keys := append([][]byte{primary}, previous...)
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, &customerr.OutOfBoundsError{
+				Err: errors.New("keys must not be empty"),
+				Op:  op,
+			}
+		}
+	}
+	return &Signer{keys: keys, tagLength: tagLength}, nil
+}
+
+// Sign returns the token of f.
+func (s *Signer) Sign(f *Timeflake) string {
+	return f.Base62 + "." + base64.RawURLEncoding.EncodeToString(s.tag(s.keys[0], f))
+}
+
+// Verify checks the tag of a token in constant time and returns its
+// Timeflake. Errors are of type *customerr.TokenError and wrap either
+// ErrMalformedToken or ErrInvalidSignature.
+func (s *Signer) Verify(token string) (*Timeflake, error) {
+	const op = "timeflake:Signer.Verify"
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return nil, &customerr.TokenError{Err: ErrMalformedToken, Op: op}
+	}
+	// Only the padded base62 form Sign produces is accepted, so an ID has
+	// exactly one valid token.
+	f, err := decodeBase62(token[:i])
+	if err != nil || f.Base62 != token[:i] {
+		return nil, &customerr.TokenError{Err: ErrMalformedToken, Op: op}
+	}
+	// Strict decoding rejects tags with padding bits set, so a tag has exactly
+	// one valid encoding.
+	tag, err := base64.RawURLEncoding.Strict().DecodeString(token[i+1:])
+	if err != nil || len(tag) != s.tagLength {
+		return nil, &customerr.TokenError{Err: ErrMalformedToken, Op: op}
+	}
+
+	valid := false
+	for _, key := range s.keys {
+		// Check every key, so the time does not reveal which one matched.
+		if hmac.Equal(tag, s.tag(key, f)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, &customerr.TokenError{Err: ErrInvalidSignature, Op: op}
+	}
+	return f, nil
+}
+
+func (s *Signer) tag(key []byte, f *Timeflake) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(f.Bytes)
+	return mac.Sum(nil)[:s.tagLength]
+}
@@ -0,0 +1,111 @@
+package timeflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// StateStore persists the highest timestamp a Generator may have issued, so
+// a restarted Generator does not go below it even if the clock was set back.
+// Timestamps are in milliseconds since the Epoch of the Generator's Layout.
+type StateStore interface {
+	// Load returns the highest reserved timestamp, or -1 if there is none.
+	Load() (int64, error)
+	// Reserve records that timestamps up to ms may be issued. A lower
+	// value than the stored one must not replace it.
+	Reserve(ms int64) error
+}
+
+// FileState is a StateStore that keeps the timestamp in a file. Every
+// operation holds an exclusive lock on a lock file next to path, so processes
+// on one host can share a FileState. On Unix systems flock(2) is used on it,
+// elsewhere it is created exclusively. The file is replaced atomically, so a
+// crash leaves either the old or the new timestamp behind.
+type FileState struct {
+	path string
+}
+
+// NewFileState returns a FileState for path. The file is created when the
+// first timestamp is reserved.
+func NewFileState(path string) *FileState {
+	return &FileState{path: path}
+}
+
+// Load implements StateStore.
+func (s *FileState) Load() (int64, error) {
+	var ms int64
+	err := s.locked(func() (err error) {
+		ms, err = s.read()
+		return err
+	})
+	return ms, err
+}
+
+// Reserve implements StateStore. The file is synced before Reserve returns.
+func (s *FileState) Reserve(ms int64) error {
+	return s.locked(func() error {
+		stored, err := s.read()
+		if err != nil || stored >= ms {
+			return err
+		}
+		return s.write(ms)
+	})
+}
+
+func (s *FileState) locked(fn func() error) error {
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock %s: %w", s.path, err)
+	}
+	err = fn()
+	if uerr := unlock(); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// read returns the stored timestamp, or -1 if the file does not exist.
+func (s *FileState) read() (int64, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("state file %s is corrupted", s.path)
+	}
+	return ms, nil
+}
+
+// write replaces the file with one holding ms. The new file is written and
+// synced next to it before it is renamed, and the rename is synced too.
+func (s *FileState) write(ms int64) error {
+	dir := filepath.Dir(s.path)
+	f, err := os.CreateTemp(dir, filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(strconv.FormatInt(ms, 10) + "\n")
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
@@ -0,0 +1,40 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package timeflake
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds the wait for the lock file. A process that crashed
+// while holding it leaves it behind, it has to be removed by hand.
+const lockTimeout = 5 * time.Second
+
+// lockFile creates the file at path exclusively, waiting for other holders
+// to remove it.
+func lockFile(path string) (func() error, error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		l, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			l.Close()
+			return func() error {
+				return os.Remove(path)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for " + path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// syncDir does nothing, directories can not be synced on these systems.
+func syncDir(dir string) error {
+	return nil
+}
@@ -22,6 +22,9 @@ const (
 	maxTimeflake = "340282366920938463463374607431768211455"
 )
 
+// Timeflake holds an ID together with all of its encodings. The fields are
+// kept for compatibility, they are not updated when one of them changes. New
+// code should prefer ID, see the ID method.
 type Timeflake struct {
 	Base62 string
 	Hex    string
@@ -193,7 +196,7 @@ func FromBytes(fromBytes []byte) (*Timeflake, error) {
 		}
 	}
 
-	u, UUIDErr := uuid.FromBytes(randomAndTimestampCombined.Bytes())
+	u, UUIDErr := uuid.FromBytes(fromBytes)
 
 	if UUIDErr != nil {
 		return nil, &customerr.UUIDError{
@@ -215,13 +218,11 @@ func FromBytes(fromBytes []byte) (*Timeflake, error) {
 }
 
 func FromHex(hexValue string) (*Timeflake, error) {
-	bigInt := utils.ASCIIToBigInt(hexValue, alphabets.HEX)
-	return FromBytes(bigInt.Bytes())
+	return decodeHex(hexValue)
 }
 
 func FromBase62(b62 string) (*Timeflake, error) {
-	bigInt := utils.ASCIIToBigInt(b62, alphabets.BASE62)
-	return FromBytes(bigInt.Bytes())
+	return decodeBase62(b62)
 }
 
 type Values interface {
@@ -0,0 +1,72 @@
+package timeflake
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// 100 nanosecond intervals between the UUID epoch, 15 Oct 1582, and the
+// Unix epoch.
+const uuidEpochOffset = 122192928000000000
+
+// FromTimeUUID creates a Timeflake with the millisecond timestamp of a
+// version 1 or 6 UUID, so legacy keys can be migrated without losing their
+// time order.
+//
+// The top 14 bits of the random part hold the sub-millisecond remainder of
+// the UUID timestamp, which keeps UUIDs of the same millisecond in order. The
+// remaining 66 bits are taken from a SHA-256 hash of the UUID if
+// deterministic is set, so the same UUID always results in the same
+// Timeflake, or are random otherwise.
+func FromTimeUUID(u uuid.UUID, deterministic bool) (*Timeflake, error) {
+	const op = "timeflake:FromTimeUUID"
+	var ticks int64
+	switch u.Version() {
+	case 1:
+		ticks = int64(u.Time())
+	case 6:
+		ticks = int64(binary.BigEndian.Uint32(u[0:4]))<<28 |
+			int64(binary.BigEndian.Uint16(u[4:6]))<<12 |
+			int64(binary.BigEndian.Uint16(u[6:8])&0x0fff)
+	default:
+		return nil, &customerr.UUIDError{
+			Err: errors.New("not a version 1 or 6 UUID"),
+			Op:  op,
+		}
+	}
+
+	unix := ticks - uuidEpochOffset
+	if unix < 0 {
+		return nil, &customerr.OutOfBoundsError{
+			Err: errors.New("UUID was created before the Unix epoch"),
+			Op:  op,
+		}
+	}
+	ms, sub := unix/10000, unix%10000
+
+	var tail []byte
+	if deterministic {
+		sum := sha256.Sum256(u[:])
+		tail = sum[:9]
+	} else {
+		tail = make([]byte, 9)
+		if _, err := io.ReadFull(rand.Reader, tail); err != nil {
+			return nil, &customerr.GeneratorError{Err: err, Op: op}
+		}
+	}
+	random := new(big.Int).SetBytes(tail)
+	random.Rsh(random, 72-66)
+	random.Or(random, new(big.Int).Lsh(big.NewInt(sub), 66))
+
+	v := new(big.Int).Lsh(big.NewInt(ms), 80)
+	v.Or(v, random)
+	return fromInt(v)
+}
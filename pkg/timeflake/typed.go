@@ -0,0 +1,102 @@
+package timeflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// Prefix names the entity of a TypedID. Implementations are usually empty
+// structs:
+//
+//	type User struct{}
+//
+//	func (User) Prefix() string { return "usr" }
+type Prefix interface {
+	Prefix() string
+}
+
+// TypedID is an ID of a specific entity, formatted like
+// "usr_02lVIoVLUfN6xUwLlnSRjj". The prefix is part of the type, so a
+// TypedID[Order] can not be passed where a TypedID[User] is expected.
+type TypedID[P Prefix] struct {
+	id ID
+}
+
+// NewTypedID returns id as TypedID of P.
+func NewTypedID[P Prefix](id ID) TypedID[P] {
+	return TypedID[P]{id: id}
+}
+
+// ParseTypedID parses a prefixed ID and rejects other prefixes.
+func ParseTypedID[P Prefix](value string) (TypedID[P], error) {
+	const op = "timeflake:ParseTypedID"
+	prefix := prefixOf[P]()
+	i := strings.LastIndexByte(value, '_')
+	if i < 0 {
+		return TypedID[P]{}, &customerr.ConversionError{
+			Err: fmt.Errorf("'%s' has no prefix, expected '%s_'", value, prefix),
+			Op:  op,
+		}
+	}
+	if value[:i] != prefix {
+		return TypedID[P]{}, &customerr.ConversionError{
+			Err: fmt.Errorf("unexpected prefix '%s', expected '%s'", value[:i], prefix),
+			Op:  op,
+		}
+	}
+	id, err := ParseID(value[i+1:])
+	if err != nil {
+		return TypedID[P]{}, err
+	}
+	return TypedID[P]{id: id}, nil
+}
+
+func prefixOf[P Prefix]() string {
+	var p P
+	return p.Prefix()
+}
+
+// ID returns the untyped ID.
+func (t TypedID[P]) ID() ID {
+	return t.id
+}
+
+// String returns the prefix and the base62 form of the ID.
+func (t TypedID[P]) String() string {
+	return prefixOf[P]() + "_" + t.id.String()
+}
+
+func (t TypedID[P]) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TypedID[P]) UnmarshalText(text []byte) error {
+	parsed, err := ParseTypedID[P](string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Value implements driver.Valuer and stores the prefixed form.
+func (t TypedID[P]) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// Scan implements sql.Scanner for prefixed IDs stored as text.
+func (t *TypedID[P]) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+	return &customerr.ConversionError{
+		Err: fmt.Errorf("can not scan %T into a typed ID", src),
+		Op:  "timeflake:TypedID.Scan",
+	}
+}
@@ -0,0 +1,28 @@
+package timeflake
+
+import (
+	"strings"
+
+	"github.com/gioni06/go-timeflake/internal/alphabets"
+	"github.com/gioni06/go-timeflake/internal/utils"
+)
+
+// A ULID has the same layout as a Timeflake, 48 bits of milliseconds followed
+// by 80 random bits. Only the encoding differs, so both convert losslessly.
+
+// crockfordAliases maps the characters Crockford's Base32 accepts as aliases
+// when decoding.
+var crockfordAliases = strings.NewReplacer("I", "1", "L", "1", "O", "0")
+
+// ToULIDString returns the Timeflake as 26 character ULID.
+func (f *Timeflake) ToULIDString() string {
+	s, _ := utils.BigIntToASCII(f.ID().Int(), alphabets.CROCKFORD32, 26)
+	return s
+}
+
+// FromULIDString creates a Timeflake from a ULID. Decoding is case
+// insensitive and accepts the aliases of Crockford's Base32.
+func FromULIDString(ulid string) (*Timeflake, error) {
+	value := crockfordAliases.Replace(strings.ToUpper(ulid))
+	return decodeAlphabet(value, alphabets.CROCKFORD32, "timeflake:FromULIDString")
+}
@@ -0,0 +1,83 @@
+package timeflake
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+// A UUIDv7 as defined by RFC 9562 shares the 48 bit millisecond prefix of a
+// Timeflake. Of the 80 random bits, 4 hold the version and 2 the variant,
+// which leaves 74 bits of randomness.
+const v7RandomBits = 74
+
+// IsUUIDv7 reports whether the version and variant bits of the Timeflake
+// mark it as a RFC 9562 UUIDv7.
+func (f *Timeflake) IsUUIDv7() bool {
+	if len(f.Bytes) != 16 {
+		return false
+	}
+	return f.Bytes[6]>>4 == 7 && f.Bytes[8]>>6 == 2
+}
+
+// ToUUIDv7 returns the Timeflake as UUIDv7. The timestamp and the upper 74
+// of the 80 random bits are kept, the random bits are moved around the
+// version and variant bits. The order of Timeflakes is preserved, Timeflakes
+// that only differ in the lowest 6 bits map to the same UUIDv7. A Timeflake
+// for which IsUUIDv7 holds, like those of a Generator WithUUIDv7, is returned
+// unchanged.
+func (f *Timeflake) ToUUIDv7() (uuid.UUID, error) {
+	var u uuid.UUID
+	if len(f.Bytes) != 16 {
+		return u, &customerr.UUIDError{
+			Err: errors.New("timeflake must be 16 Bytes"),
+			Op:  "timeflake:ToUUIDv7",
+		}
+	}
+	if f.IsUUIDv7() {
+		copy(u[:], f.Bytes)
+		return u, nil
+	}
+	v := new(big.Int).SetBytes(f.Bytes)
+	ts := new(big.Int).Lsh(new(big.Int).Rsh(v, 80), 80)
+	random := new(big.Int).Rsh(v.Xor(v, ts), 80-v7RandomBits)
+	v = packUUIDv7(random)
+	v.Or(v, ts).FillBytes(u[:])
+	return u, nil
+}
+
+// FromUUIDv7 creates a Timeflake from a UUIDv7. The Bytes are taken as they
+// are, so the Timeflake has the same time, ordering and UUID as u.
+func FromUUIDv7(u uuid.UUID) (*Timeflake, error) {
+	if u.Version() != 7 || u.Variant() != uuid.RFC4122 {
+		return nil, &customerr.UUIDError{
+			Err: errors.New("not a UUIDv7"),
+			Op:  "timeflake:FromUUIDv7",
+		}
+	}
+	return FromBytes(u[:])
+}
+
+// packUUIDv7 spreads the 74 bit random value around the version and variant
+// bits, so incrementing random keeps the resulting UUIDv7 increasing.
+func packUUIDv7(random *big.Int) *big.Int {
+	randB := new(big.Int).And(random, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 62), big.NewInt(1)))
+	randA := new(big.Int).Rsh(random, 62)
+
+	v := new(big.Int).Lsh(big.NewInt(7), 76)
+	v.Or(v, randA.Lsh(randA, 64))
+	v.Or(v, new(big.Int).Lsh(big.NewInt(2), 62))
+	return v.Or(v, randB)
+}
+
+// unpackUUIDv7 is the inverse of packUUIDv7, it returns the 74 bit random
+// value of the 80 bit random part of a UUIDv7.
+func unpackUUIDv7(v *big.Int) *big.Int {
+	randB := new(big.Int).And(v, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 62), big.NewInt(1)))
+	randA := new(big.Int).Rsh(v, 64)
+	randA.And(randA, big.NewInt(0xfff))
+	return randA.Lsh(randA, 62).Or(randA, randB)
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+)
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	const key = "000102030405060708090a0b0c0d0e0f"
+
+	var opaque bytes.Buffer
+	e := app.NewEncrypt()
+	e.Key = key
+	e.ID = "02lVIoVLUfN6xUwLlnSRjj"
+	e.Out = &opaque
+	if err := e.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := app.NewDecrypt()
+	d.Key = key
+	d.In = strings.NewReader(opaque.String())
+	d.Out = &out
+	if err := d.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "02lVIoVLUfN6xUwLlnSRjj\n" {
+		t.Errorf("expected the original timeflake got %q", out.String())
+	}
+}
+
+func TestEncryptRequiresKey(t *testing.T) {
+	e := app.NewEncrypt()
+	e.Key = "not-hex"
+	e.ID = "02lVIoVLUfN6xUwLlnSRjj"
+	if err := e.Run(); err == nil {
+		t.Error("invalid keys should be rejected")
+	}
+}
+
+func TestDecryptWithPreviousKey(t *testing.T) {
+	const old = "000102030405060708090a0b0c0d0e0f"
+
+	var opaque bytes.Buffer
+	e := app.NewEncrypt()
+	e.Key = old
+	e.ID = "02lVIoVLUfN6xUwLlnSRjj"
+	e.Out = &opaque
+	if err := e.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	d := app.NewDecrypt()
+	d.Key = "ffeeddccbbaa99887766554433221100"
+	d.PreviousKeys = []string{old}
+	d.KeyIndex = 1
+	d.In = strings.NewReader(opaque.String())
+	d.Out = &out
+	if err := d.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "02lVIoVLUfN6xUwLlnSRjj\n" {
+		t.Errorf("expected the original timeflake got %q", out.String())
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+)
+
+func TestConvert(t *testing.T) {
+	var out bytes.Buffer
+	c := app.NewConvert()
+	c.In = strings.NewReader("02lVIoVLUfN6xUwLlnSRjj\n0177487e-c2f8-d0a6-3f27-85a9cadfc50f\n")
+	c.To = "hex"
+	c.Out = &out
+
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "0177487ec2f8d0a63f2785a9cadfc50f\n0177487ec2f8d0a63f2785a9cadfc50f\n"
+	if out.String() != expected {
+		t.Errorf("expected %q got %q", expected, out.String())
+	}
+}
+
+func TestConvertTimeUUID(t *testing.T) {
+	var out bytes.Buffer
+	c := app.NewConvert()
+	c.ID = "c232ab00-9414-11ec-b3c8-9f6bdeced846"
+	c.TimeUUID = true
+	c.To = "hex"
+	c.Out = &out
+
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(out.String(), "017f22e279b0") {
+		t.Errorf("timestamp of the UUID should be kept %s", out.String())
+	}
+
+	c.ID = "0177487e-c2f8-d0a6-3f27-85a9cadfc50f"
+	if err := c.Run(); err == nil {
+		t.Error("converting a timeflake UUID as time UUID should fail")
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+)
+
+func TestRangeSQL(t *testing.T) {
+	var out bytes.Buffer
+	r := app.NewRange()
+	r.From = "2021-01-01"
+	r.To = "2021-01-02"
+	r.SQL = "id"
+	r.Out = &out
+
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id BETWEEN '0176bb3e700000000000000000000000' AND '0176c064cbffffffffffffffffffffff'\n"
+	if out.String() != expected {
+		t.Errorf("expected %q got %q", expected, out.String())
+	}
+}
+
+func TestRangeSQLInt(t *testing.T) {
+	var out bytes.Buffer
+	r := app.NewRange()
+	r.From = "2021-01-01"
+	r.To = "2021-01-02"
+	r.Encoding = "int"
+	r.SQL = "id"
+	r.Out = &out
+
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id BETWEEN 1945716782496305379819262260019200000 AND 1945821233687120083779956873625599999\n"
+	if out.String() != expected {
+		t.Errorf("expected %q got %q", expected, out.String())
+	}
+}
+
+func TestRangeStep(t *testing.T) {
+	var out bytes.Buffer
+	r := app.NewRange()
+	r.From = "2021-01-01"
+	r.To = "2021-01-02"
+	r.Step = "hour"
+	r.Out = &out
+
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 24 {
+		t.Errorf("expected 24 buckets got %d", lines)
+	}
+}
+
+func TestRangeRejectsEmptyWindow(t *testing.T) {
+	r := app.NewRange()
+	r.From = "2021-01-02"
+	r.To = "2021-01-01"
+
+	if err := r.Run(); err == nil {
+		t.Error("range with --from after --to should fail")
+	}
+}
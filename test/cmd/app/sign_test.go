@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	var token bytes.Buffer
+	s := app.NewSign()
+	s.Key = "736563726574"
+	s.ID = "0177487ec2f8d0a63f2785a9cadfc50f"
+	s.Out = &token
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if token.String() != "02lVIoVLUfN6xUwLlnSRjj.kQFvgyVH9iM\n" {
+		t.Errorf("token is not correct %q", token.String())
+	}
+
+	var out bytes.Buffer
+	v := app.NewVerify()
+	v.Key = "736563726574"
+	v.ID = "02lVIoVLUfN6xUwLlnSRjj.kQFvgyVH9iM"
+	v.To = "hex"
+	v.Out = &out
+	if err := v.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "0177487ec2f8d0a63f2785a9cadfc50f\n" {
+		t.Errorf("expected the timeflake got %q", out.String())
+	}
+
+	v.ID = "02lVIoVLUfN6xUwLlnSRjj.kQFvgyVH9iN"
+	if err := v.Run(); err == nil {
+		t.Error("verifying a forged token should fail")
+	}
+}
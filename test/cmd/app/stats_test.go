@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+)
+
+// Timeflakes from 2021-01-01T00, 01, 03, 04 and 00 again, the last one is
+// out of order.
+const statsInput = `0176bb3e700000000000000000000001
+0176bb755e8000000000000000000001
+0176bbe33b8000000000000000000001
+0176bc1a2a0000000000000000000001
+0176bc1a2a0000000000000000000002
+invalid
+0176bb3e700000000000000000000002
+`
+
+func TestStatsJSON(t *testing.T) {
+	var out bytes.Buffer
+	s := app.NewStats()
+	s.In = strings.NewReader(statsInput)
+	s.JSON = true
+	s.Ordered = true
+	s.Out = &out
+
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var report struct {
+		Total     int `json:"total"`
+		Malformed int `json:"malformed"`
+		PeakPerMs int `json:"peak_per_ms"`
+		SharedMs  int `json:"shared_ms"`
+		Backwards int `json:"backwards"`
+		Buckets   []struct {
+			Count int `json:"count"`
+		} `json:"buckets"`
+		Gaps []struct{} `json:"gaps"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Total != 6 || report.Malformed != 1 {
+		t.Errorf("expected 6 timeflakes and 1 malformed line got %d and %d", report.Total, report.Malformed)
+	}
+	// Ordered input is counted per run, the timeflake going backwards does
+	// not count as sharing its millisecond with the first one.
+	if report.PeakPerMs != 2 || report.SharedMs != 2 {
+		t.Errorf("expected a peak of 2 and 2 shared got %d and %d", report.PeakPerMs, report.SharedMs)
+	}
+	if report.Backwards != 1 {
+		t.Errorf("expected 1 timeflake going backwards got %d", report.Backwards)
+	}
+	if len(report.Buckets) != 4 || report.Buckets[0].Count != 2 {
+		t.Errorf("buckets are not correct %+v", report.Buckets)
+	}
+	if len(report.Gaps) != 1 {
+		t.Errorf("expected 1 gap got %d", len(report.Gaps))
+	}
+}
+
+func TestStatsUnorderedSharedMs(t *testing.T) {
+	var out bytes.Buffer
+	s := app.NewStats()
+	s.In = strings.NewReader(statsInput)
+	s.JSON = true
+	s.Out = &out
+
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		PeakPerMs int `json:"peak_per_ms"`
+		SharedMs  int `json:"shared_ms"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.PeakPerMs != 2 || report.SharedMs != 4 {
+		t.Errorf("expected a peak of 2 and 4 shared got %d and %d", report.PeakPerMs, report.SharedMs)
+	}
+}
+
+func TestStatsTable(t *testing.T) {
+	var out bytes.Buffer
+	s := app.NewStats()
+	s.In = strings.NewReader(statsInput)
+	s.Interval = "day"
+	s.Out = &out
+
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "2021-01-01T00:00:00Z  6") {
+		t.Errorf("expected a single bucket with 6 timeflakes: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "backwards=0\tgaps=0") {
+		t.Errorf("unordered input should not report timeflakes going backwards: %s", out.String())
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/cmd/app"
+	"github.com/gioni06/go-timeflake/internal/customerr"
+)
+
+const validateInput = `02lVIoVLUfN6xUwLlnSRjj
+0177487ec2f8d0a63f2785a9cadfc50f
+not-an-id
+
+02lVIoVLUfN6xUwLlnSRjk
+0177487ec2f800000000000000000000
+`
+
+func TestValidateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ids.txt")
+	if err := ioutil.WriteFile(path, []byte(validateInput), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	v := app.NewValidate()
+	v.Input = path
+	v.NotBefore = "2021-01-28"
+	v.Out = &out
+
+	err = v.Run()
+	if _, ok := err.(*customerr.ValidationError); !ok {
+		t.Fatalf("expected a validation error got %v", err)
+	}
+
+	expected := "ids=5\tvalid=4\tmalformed=1\tduplicates=1\ttoo_old=0\tfuture=0\t\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("expected summary %q got %q", expected, out.String())
+	}
+	if !strings.Contains(out.String(), "line=2\tid=0177487ec2f8d0a63f2785a9cadfc50f\tproblem=duplicate of line 1") {
+		t.Errorf("duplicate was not reported: %s", out.String())
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	var out bytes.Buffer
+	v := app.NewValidate()
+	v.In = strings.NewReader(validateInput)
+	v.NotAfter = "2021-01-01"
+	v.Quiet = true
+	v.Out = &out
+
+	if err := v.Run(); err == nil {
+		t.Fatal("validation should fail")
+	}
+
+	expected := "ids=5\tvalid=4\tmalformed=1\tduplicates=1\ttoo_old=0\tfuture=4\t\n"
+	if out.String() != expected {
+		t.Errorf("expected %q got %q", expected, out.String())
+	}
+}
+
+func TestValidateSucceeds(t *testing.T) {
+	var out bytes.Buffer
+	v := app.NewValidate()
+	// Random parts of zero and 2^80-1, like MinForTime and MaxForTime
+	// return, are valid.
+	v.In = strings.NewReader("02lVIoVLUfN6xUwLlnSRjj\n0177487ec2f800000000000000000000\n0177487ec2f8ffffffffffffffffffff\n")
+	v.Out = &out
+
+	if err := v.Run(); err != nil {
+		t.Error(err)
+	}
+}
+
+// pipe hides the Seek method of the reader, like stdin from a pipe.
+type pipe struct{ r *strings.Reader }
+
+func (p pipe) Read(b []byte) (int, error) { return p.r.Read(b) }
+
+func TestValidatePipe(t *testing.T) {
+	var out bytes.Buffer
+	v := app.NewValidate()
+	v.In = pipe{strings.NewReader(validateInput)}
+	v.Out = &out
+
+	if err := v.Run(); err == nil {
+		t.Fatal("validation should fail")
+	}
+	expected := "ids=5\tvalid=4\tmalformed=1\tduplicates=1\ttoo_old=0\tfuture=0\t\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("expected summary %q got %q", expected, out.String())
+	}
+	if !strings.Contains(out.String(), "line=2\tid=0177487ec2f8d0a63f2785a9cadfc50f\tproblem=duplicate of line 1") {
+		t.Errorf("duplicate was not reported: %s", out.String())
+	}
+}
+
+func TestValidateReportsLongLines(t *testing.T) {
+	var out bytes.Buffer
+	v := app.NewValidate()
+	v.In = pipe{strings.NewReader("02lVIoVLUfN6xUwLlnSRjj\n" + strings.Repeat("x", 100000) + "\n02lVIoVLUfN6xUwLlnSRjk\n")}
+	v.Out = &out
+
+	if err := v.Run(); err == nil {
+		t.Fatal("validation should fail")
+	}
+	expected := "ids=3\tvalid=2\tmalformed=1\tduplicates=0\ttoo_old=0\tfuture=0\t\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("expected summary %q got %q", expected, out.String())
+	}
+	if !strings.Contains(out.String(), "line=2\t") {
+		t.Errorf("long line was not reported: %s", out.String())
+	}
+}
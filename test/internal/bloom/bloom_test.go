@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/bloom"
+)
+
+func TestFilterFindsAddedKeys(t *testing.T) {
+	f := bloom.New(1000, 0.01)
+	key := make([]byte, 8)
+	for i := uint64(0); i < 1000; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		f.Add(key)
+	}
+	for i := uint64(0); i < 1000; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		if !f.Add(key) {
+			t.Fatalf("key %d should have been seen", i)
+		}
+	}
+}
+
+func TestFilterFalsePositiveRate(t *testing.T) {
+	f := bloom.New(10000, 0.01)
+	key := make([]byte, 8)
+	falsePositives := 0
+	for i := uint64(0); i < 10000; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		if f.Add(key) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 200 {
+		t.Errorf("too many false positives %d", falsePositives)
+	}
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/server"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func request(t *testing.T, h http.Handler, method, target, body string, v interface{}) int {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected a JSON response got '%s'", ct)
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code
+}
+
+func TestIssueID(t *testing.T) {
+	h := server.New(timeflake.NewGenerator())
+
+	var res struct {
+		ID string `json:"id"`
+	}
+	if code := request(t, h, http.MethodGet, "/v1/id?encoding=hex", "", &res); code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", code)
+	}
+	if _, err := timeflake.FromHex(res.ID); err != nil {
+		t.Errorf("issued id '%s' is not a timeflake", res.ID)
+	}
+}
+
+func TestIssueBatchIsOrdered(t *testing.T) {
+	h := server.New(timeflake.NewGenerator())
+
+	var res struct {
+		IDs []string `json:"ids"`
+	}
+	if code := request(t, h, http.MethodGet, "/v1/ids?count=100", "", &res); code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", code)
+	}
+	if len(res.IDs) != 100 {
+		t.Fatalf("expected 100 ids got %d", len(res.IDs))
+	}
+	for i := 1; i < len(res.IDs); i++ {
+		if res.IDs[i-1] >= res.IDs[i] {
+			t.Fatalf("ids are not increasing: %s >= %s", res.IDs[i-1], res.IDs[i])
+		}
+	}
+}
+
+func TestIssueRejectsInvalidQueries(t *testing.T) {
+	h := server.New(timeflake.NewGenerator())
+
+	for _, target := range []string{"/v1/ids?count=0", "/v1/ids?count=abc", "/v1/ids?count=10001", "/v1/id?encoding=morse"} {
+		if code := request(t, h, http.MethodGet, target, "", nil); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 got %d", target, code)
+		}
+	}
+	if code := request(t, h, http.MethodPost, "/v1/id", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 got %d", code)
+	}
+}
+
+func TestParse(t *testing.T) {
+	h := server.New(timeflake.NewGenerator())
+
+	for _, body := range []string{"02lVIoVLUfN6xUwLlnSRjj", `{"id": "0177487ec2f8d0a63f2785a9cadfc50f"}`} {
+		var res struct {
+			Base62    string `json:"base62"`
+			UUID      string `json:"uuid"`
+			Timestamp int64  `json:"timestamp"`
+			Random    string `json:"random"`
+		}
+		if code := request(t, h, http.MethodPost, "/v1/parse", body, &res); code != http.StatusOK {
+			t.Fatalf("expected 200 got %d", code)
+		}
+		if res.Base62 != "02lVIoVLUfN6xUwLlnSRjj" || res.UUID != "0177487e-c2f8-d0a6-3f27-85a9cadfc50f" {
+			t.Errorf("parsed timeflake is not correct %+v", res)
+		}
+		if res.Timestamp != 1611829003000 || res.Random != "985318938706034770822415" {
+			t.Errorf("parsed components are not correct %+v", res)
+		}
+	}
+
+	if code := request(t, h, http.MethodPost, "/v1/parse", "not-an-id", nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 got %d", code)
+	}
+}
+
+func TestHealthAndMetrics(t *testing.T) {
+	h := server.New(timeflake.NewGenerator())
+
+	if code := request(t, h, http.MethodGet, "/healthz", "", nil); code != http.StatusOK {
+		t.Errorf("expected 200 got %d", code)
+	}
+	request(t, h, http.MethodGet, "/v1/ids?count=5", "", nil)
+	request(t, h, http.MethodPost, "/v1/parse", "invalid", nil)
+
+	var res struct {
+		Requests uint64 `json:"requests"`
+		Issued   uint64 `json:"issued"`
+		Failures uint64 `json:"failures"`
+	}
+	request(t, h, http.MethodGet, "/metrics", "", &res)
+	if res.Requests != 4 || res.Issued != 5 || res.Failures != 1 {
+		t.Errorf("counters are not correct %+v", res)
+	}
+}
+
+func TestIssueGivesUpWithRequestContext(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	gen := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 20))),
+		timeflake.WithClockWait(),
+	)
+	gen.Next()
+	h := server.New(gen)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/v1/id", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 got %d", rec.Code)
+	}
+}
@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/alphabets"
+	"github.com/gioni06/go-timeflake/internal/utils"
+)
+
+func FuzzASCIIToBigInt(f *testing.F) {
+	for _, seed := range []string{"8M0kX", "0", "", "zzzz", "not base62!", "ö"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, alphabet := range []string{alphabets.BASE62, alphabets.HEX, alphabets.CROCKFORD32} {
+			v := utils.ASCIIToBigInt(input, alphabet)
+			if v == nil || v.Sign() < 0 {
+				t.Fatalf("%q: invalid result %v", input, v)
+			}
+			// Whatever was decoded has to survive a round trip.
+			s, err := utils.BigIntToASCII(v, alphabet, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if utils.ASCIIToBigInt(s, alphabet).Cmp(v) != 0 {
+				t.Fatalf("%q: round trip of %s returned %q", input, v, s)
+			}
+			if v.Cmp(new(big.Int)) == 0 && s != alphabet[:1] {
+				t.Fatalf("%q: zero encoded as %q", input, s)
+			}
+		}
+	})
+}
@@ -14,22 +14,22 @@ func TestBigIntToASCII(t *testing.T) {
 	b1 := big.NewInt(1504324233)
 	res1, _ := utils.BigIntToASCII(b1, alphabets.BASE62, 5)
 
-	if res1 != "01dnzS5" {
-		t.Errorf("expected '01dnzS5' got '%s'", res1)
+	if res1 != "1dnzS5" {
+		t.Errorf("expected '1dnzS5' got '%s'", res1)
 	}
 
 	b2 := big.NewInt(1504324233)
 	res2, _ := utils.BigIntToASCII(b2, alphabets.HEX, 5)
 
-	if res2 != "059aa2a89" {
-		t.Errorf("expected '059aa2a89' got '%s'", res2)
+	if res2 != "59aa2a89" {
+		t.Errorf("expected '59aa2a89' got '%s'", res2)
 	}
 
 	b3 := big.NewInt(0)
 	res3, _ := utils.BigIntToASCII(b3, alphabets.HEX, 5)
 
-	if res3 != "0" {
-		t.Errorf("expected '0' got '%s'", res3)
+	if res3 != "00000" {
+		t.Errorf("expected '00000' got '%s'", res3)
 	}
 }
 
@@ -37,8 +37,26 @@ func TestBigIntToASCIIWithZeroValue(t *testing.T) {
 	b1 := big.NewInt(0)
 	res1, _ := utils.BigIntToASCII(b1, alphabets.BASE62, 5)
 
-	if res1 != "0" {
-		t.Errorf("expected '0' got '%s'", res1)
+	if res1 != "00000" {
+		t.Errorf("expected '00000' got '%s'", res1)
+	}
+
+	res2, _ := utils.BigIntToASCII(b1, alphabets.BASE62, 0)
+
+	if res2 != "0" {
+		t.Errorf("expected '0' got '%s'", res2)
+	}
+}
+
+func TestBigIntToASCIIPadding(t *testing.T) {
+	b := big.NewInt(0xabc)
+
+	for padding, expected := range map[int]string{0: "abc", 2: "abc", 3: "abc", 4: "0abc", 6: "000abc"} {
+		res, _ := utils.BigIntToASCII(b, alphabets.HEX, padding)
+
+		if res != expected {
+			t.Errorf("padding %d: expected '%s' got '%s'", padding, expected, res)
+		}
 	}
 }
 
@@ -75,6 +93,10 @@ func TestFillString(t *testing.T) {
 	if s != "xxx" {
 		t.Errorf("expected 'xxx' got '%s'", s)
 	}
+
+	if s := utils.FillString("x", 0); s != "" {
+		t.Errorf("expected '' got '%s'", s)
+	}
 }
 
 func TestIndexAlphabet(t *testing.T) {
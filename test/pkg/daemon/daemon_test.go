@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/daemon"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func startDaemon(t *testing.T) (string, func()) {
+	return startDaemonWith(t, timeflake.NewGenerator())
+}
+
+func startDaemonWith(t *testing.T, gen *timeflake.Generator) (string, func()) {
+	dir, err := ioutil.TempDir("", "timeflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "timeflake.sock")
+	l, err := daemon.Listen(path, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := daemon.NewServer(gen)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(l)
+	}()
+	return path, func() {
+		srv.Close()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClientNext(t *testing.T) {
+	path, stop := startDaemon(t)
+	defer stop()
+
+	c, err := daemon.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	a, err := c.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	flakes, err := c.NextN(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flakes) != 100 {
+		t.Fatalf("expected 100 timeflakes got %d", len(flakes))
+	}
+	for _, f := range flakes {
+		if a.Int.Cmp(&f.Int) >= 0 {
+			t.Fatalf("timeflakes are not increasing: %s >= %s", a.Base62, f.Base62)
+		}
+		a = f
+	}
+
+	if _, err := c.NextN(0); err == nil {
+		t.Error("NEXT 0 should fail")
+	}
+}
+
+func TestClientParseAndRange(t *testing.T) {
+	path, stop := startDaemon(t)
+	defer stop()
+
+	c, err := daemon.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	components, err := c.Parse("0177487ec2f8d0a63f2785a9cadfc50f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components["base62"] != "02lVIoVLUfN6xUwLlnSRjj" || components["ts"] != "1611829003000" {
+		t.Errorf("parsed components are not correct %v", components)
+	}
+	if _, err := c.Parse("invalid"); err == nil {
+		t.Error("parsing an invalid id should fail")
+	}
+
+	from := time.Unix(1611829003, 0)
+	min, max, err := c.Range(from, from.Add(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min.Hex != "0177487ec2f800000000000000000000" || max.Hex != "0177487ec2f8ffffffffffffffffffff" {
+		t.Errorf("range is not correct %s, %s", min.Hex, max.Hex)
+	}
+}
+
+func TestConcurrentClientsGetUniqueIDs(t *testing.T) {
+	path, stop := startDaemon(t)
+	defer stop()
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := daemon.Dial(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer c.Close()
+
+			var prev *timeflake.Timeflake
+			for j := 0; j < 200; j++ {
+				f, err := c.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if prev != nil && prev.Int.Cmp(&f.Int) >= 0 {
+					t.Errorf("timeflakes are not increasing: %s >= %s", prev.Base62, f.Base62)
+				}
+				prev = f
+				mu.Lock()
+				if seen[f.Base62] {
+					t.Errorf("duplicate timeflake %s", f.Base62)
+				}
+				seen[f.Base62] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestListenSetsPermissionsAndRemovesStaleSockets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "timeflake.sock")
+
+	l, err := daemon.Listen(path, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600 got %o", info.Mode().Perm())
+	}
+
+	if _, err := daemon.Listen(path, 0600); err == nil {
+		t.Error("a socket in use must not be replaced")
+	}
+
+	// Leave a stale socket behind, like a crashed daemon would.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	l, err = daemon.Listen(path, 0600)
+	if err != nil {
+		t.Fatalf("stale socket should be replaced: %s", err)
+	}
+	l.Close()
+}
+
+func TestListenKeepsFilesThatAreNotSockets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "notes.txt")
+	if err := ioutil.WriteFile(file, []byte("notes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty")
+	if err := os.Mkdir(empty, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{file, empty} {
+		if _, err := daemon.Listen(path, 0600); err == nil {
+			t.Errorf("Listen should refuse %s", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Listen removed %s: %s", path, err)
+		}
+	}
+}
+
+func TestCloseCancelsWaitingRequests(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	gen := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 20))),
+		timeflake.WithClockWait(),
+	)
+	gen.Next()
+	path, stop := startDaemonWith(t, gen)
+
+	c, err := daemon.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Next()
+		errs <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not cancel the waiting request")
+	}
+	if err := <-errs; err == nil {
+		t.Error("the waiting request should fail")
+	}
+}
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func assertAscending(t *testing.T, ids []timeflake.ID) {
+	t.Helper()
+	for i := 1; i < len(ids); i++ {
+		if bytes.Compare(ids[i-1][:], ids[i][:]) >= 0 {
+			t.Fatalf("IDs %d and %d are not ascending: %x >= %x", i-1, i, ids[i-1], ids[i])
+		}
+	}
+}
+
+func TestNewBatchIsAscendingAfterNext(t *testing.T) {
+	g := timeflake.NewGenerator()
+	before, _ := g.Next()
+	ids, err := g.NewBatch(10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := g.Next()
+
+	all := append([]timeflake.ID{before.ID()}, ids...)
+	assertAscending(t, append(all, after.ID()))
+}
+
+func TestBatchSameTimestamp(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }))
+	ids, err := g.NewBatch(1000, timeflake.SameTimestamp(), timeflake.RandomGaps(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAscending(t, ids)
+	for _, id := range ids {
+		if !id.Time().Equal(now.UTC()) {
+			t.Fatalf("ID %s has time %s, want %s", id, id.Time(), now.UTC())
+		}
+	}
+}
+
+func TestBatchSameTimestampMovesToNextTimestamp(t *testing.T) {
+	tiny := timeflake.Layout{Name: "tiny", Epoch: time.Unix(0, 0), TimestampBits: 48, RandomBits: 16}
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 4))),
+		timeflake.WithLayout(tiny),
+	)
+	ids, err := g.NewBatch(1000, timeflake.SameTimestamp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAscending(t, ids)
+	want := now.Add(time.Millisecond).UTC()
+	for _, id := range ids {
+		if got := tiny.Time(id); !got.Equal(want) {
+			t.Fatalf("ID %x has time %s, want %s", id, got, want)
+		}
+	}
+
+	if _, err := g.NewBatch(1<<16, timeflake.SameTimestamp()); err == nil {
+		t.Error("a batch larger than the random part should fail")
+	}
+}
+
+func TestBatchSameTimestampDoesNotWaitForClock(t *testing.T) {
+	tiny := timeflake.Layout{Name: "tiny", Epoch: time.Unix(0, 0), TimestampBits: 48, RandomBits: 16}
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 4))),
+		timeflake.WithLayout(tiny),
+		timeflake.WithClockWait(),
+	)
+	// The clock never moves, so waiting for it would not return.
+	ids, err := g.NewBatch(1000, timeflake.SameTimestamp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tiny.Time(ids[0]); !got.Equal(now.Add(time.Millisecond).UTC()) {
+		t.Errorf("batch should move to the next timestamp, got %s", got)
+	}
+}
+
+func TestBatchContextStopsWaitingForClock(t *testing.T) {
+	tiny := timeflake.Layout{Name: "tiny", Epoch: time.Unix(0, 0), TimestampBits: 48, RandomBits: 16}
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 4))),
+		timeflake.WithLayout(tiny),
+		timeflake.WithClockWait(),
+	)
+	if _, err := g.NewBatch(1000, timeflake.SameTimestamp()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The batch ran ahead of the clock, which never moves.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := g.NewBatchContext(ctx, 10); err == nil {
+		t.Error("NewBatchContext should stop waiting when the context is done")
+	}
+	if err := g.FillBatchContext(ctx, make([]timeflake.ID, 10)); err == nil {
+		t.Error("FillBatchContext should fail when the context is done")
+	}
+}
+
+func TestFailedBatchKeepsGeneratorState(t *testing.T) {
+	tiny := timeflake.Layout{Name: "tiny", Epoch: time.Unix(0, 0), TimestampBits: 48, RandomBits: 16}
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 4))),
+		timeflake.WithLayout(tiny),
+	)
+	ids, err := g.NewBatch(1000, timeflake.SameTimestamp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.NewBatch(1<<16, timeflake.SameTimestamp()); err == nil {
+		t.Fatal("a batch larger than the random part should fail")
+	}
+	next, err := g.NewBatch(1, timeflake.SameTimestamp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Compare(ids[999][:], next[0][:]) >= 0 || !tiny.Time(next[0]).Equal(tiny.Time(ids[999])) {
+		t.Errorf("ID after a failed batch should follow the last one: %x, %x", ids[999], next[0])
+	}
+}
+
+func TestBatchCarriesIntoNextTimestamp(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))),
+	)
+	ids, err := g.NewBatch(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAscending(t, ids)
+	if got := ids[9].Time(); !got.Equal(now.Add(time.Millisecond).UTC()) {
+		t.Errorf("batch should continue in the next millisecond, got %s", got)
+	}
+	f, _ := g.Next()
+	if bytes.Compare(ids[9][:], f.Bytes) >= 0 {
+		t.Errorf("Next after a batch is not greater: %x >= %s", ids[9], f.Hex)
+	}
+}
+
+func TestBatchUUIDv7(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 1000))),
+		timeflake.WithUUIDv7(),
+	)
+	ids, err := g.NewBatch(100, timeflake.RandomGaps(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAscending(t, ids)
+	for _, id := range ids {
+		if !id.Timeflake().IsUUIDv7() {
+			t.Fatalf("ID %s is not a UUIDv7", id.UUIDString())
+		}
+	}
+}
+
+func TestBatchRejectsWideGaps(t *testing.T) {
+	if _, err := timeflake.NewGenerator().NewBatch(10, timeflake.RandomGaps(33)); err == nil {
+		t.Error("gaps wider than 32 bits should be rejected")
+	}
+}
+
+func TestFillBatchDoesNotAllocatePerID(t *testing.T) {
+	g := timeflake.NewGenerator()
+	small := make([]timeflake.ID, 10)
+	large := make([]timeflake.ID, 10000)
+	a := testing.AllocsPerRun(10, func() { g.FillBatch(small, timeflake.RandomGaps(8)) })
+	b := testing.AllocsPerRun(10, func() { g.FillBatch(large, timeflake.RandomGaps(8)) })
+	// The first ID is created like Next does, which allocates a few times
+	// depending on the values involved.
+	if b > a+10 {
+		t.Errorf("a batch of 10000 made %v allocations, one of 10 only %v", b, a)
+	}
+}
+
+func BenchmarkNextPerID(b *testing.B) {
+	g := timeflake.NewGenerator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.Next()
+	}
+}
+
+func BenchmarkFillBatchPerID(b *testing.B) {
+	g := timeflake.NewGenerator()
+	ids := make([]timeflake.ID, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i += len(ids) {
+		g.FillBatch(ids)
+	}
+}
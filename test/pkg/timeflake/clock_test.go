@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+type fakeClocks struct {
+	wall    time.Time
+	elapsed time.Duration
+}
+
+func (f *fakeClocks) advance(d time.Duration) {
+	f.wall = f.wall.Add(d)
+	f.elapsed += d
+}
+
+func newFakeClock(t *testing.T, opts ...timeflake.ClockOption) (*timeflake.MonotonicClock, *fakeClocks) {
+	f := &fakeClocks{wall: time.Unix(1611829003, 0)}
+	c, err := timeflake.NewMonotonicClock(append([]timeflake.ClockOption{
+		timeflake.WithWallClock(func() time.Time { return f.wall }),
+		timeflake.WithElapsed(func() time.Duration { return f.elapsed }),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, f
+}
+
+func TestMonotonicClockFollowsMonotonicTime(t *testing.T) {
+	c, f := newFakeClock(t)
+	start := c.Now()
+	f.advance(1500 * time.Millisecond)
+	if d := c.Now().Sub(start); d != 1500*time.Millisecond {
+		t.Errorf("clock advanced %s, want 1.5s", d)
+	}
+}
+
+func TestMonotonicClockIgnoresWallClockStepBack(t *testing.T) {
+	c, f := newFakeClock(t, timeflake.WithMaxSlew(0.01))
+	prev := c.Now()
+
+	f.wall = f.wall.Add(-time.Hour)
+	for i := 0; i < 1000; i++ {
+		f.advance(time.Second)
+		now := c.Now()
+		if !now.After(prev) {
+			t.Fatalf("clock went backwards from %s to %s", prev, now)
+		}
+		if d := now.Sub(prev); d < 990*time.Millisecond || d > time.Second {
+			t.Fatalf("clock advanced %s in a second, more than the slew rate allows", d)
+		}
+		prev = now
+	}
+	if d := c.Drift(); d != -time.Hour+10*time.Second {
+		t.Errorf("drift after 1000s at 1%% slew = %s, want -59m50s", d)
+	}
+}
+
+func TestMonotonicClockSlewsForwardGradually(t *testing.T) {
+	c, f := newFakeClock(t, timeflake.WithMaxSlew(0.1))
+	prev := c.Now()
+
+	f.wall = f.wall.Add(time.Second)
+	for i := 0; i < 20; i++ {
+		f.advance(time.Second)
+		now := c.Now()
+		if d := now.Sub(prev); d > 1100*time.Millisecond {
+			t.Fatalf("clock jumped %s forward", d)
+		}
+		prev = now
+	}
+	if d := c.Drift(); d != 0 {
+		t.Errorf("clock should have caught up with the wall clock, drift %s", d)
+	}
+}
+
+func TestMonotonicClockToleratesDriftBelowThreshold(t *testing.T) {
+	c, f := newFakeClock(t, timeflake.WithSlewThreshold(time.Second))
+	f.wall = f.wall.Add(500 * time.Millisecond)
+	f.advance(time.Minute)
+	c.Now()
+	if d := c.Drift(); d != 500*time.Millisecond {
+		t.Errorf("drift below the threshold should be kept, got %s", d)
+	}
+}
+
+func TestMonotonicClockRejectsInvalidSlew(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1, 2} {
+		if _, err := timeflake.NewMonotonicClock(timeflake.WithMaxSlew(rate)); err == nil {
+			t.Errorf("slew rate %v should be rejected", rate)
+		}
+	}
+}
+
+func TestMonotonicClockDrivesGenerator(t *testing.T) {
+	c, err := timeflake.NewMonotonicClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := timeflake.NewGenerator(timeflake.WithClock(c.Now))
+	f, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(f.Time()); d < 0 || d > time.Second {
+		t.Errorf("timeflake time %s is far from now", f.Time())
+	}
+}
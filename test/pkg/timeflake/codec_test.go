@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func mustKey(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestCodecMatchesAES(t *testing.T) {
+	// Example vector of FIPS-197, appendix C.1.
+	c, err := timeflake.NewCodec(mustKey("000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tf, err := timeflake.Parse("00112233445566778899aabbccddeeff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opaque, err := c.Encrypt(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opaque.Hex != "69c4e0d86a7b0430d8cdb78070b4c55a" {
+		t.Errorf("opaque ID is not correct %s", opaque.Hex)
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	c, _ := timeflake.NewCodec(mustKey("000102030405060708090a0b0c0d0e0f"))
+	for i := 0; i < 100; i++ {
+		tf, _ := timeflake.Random()
+		opaque, err := c.Encrypt(tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(opaque.Base62) != 22 || opaque.Base62 == tf.Base62 {
+			t.Fatalf("opaque ID is not correct %s", opaque.Base62)
+		}
+		back, err := c.Decrypt(opaque)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.Base62 != tf.Base62 {
+			t.Fatalf("expected %s got %s", tf.Base62, back.Base62)
+		}
+	}
+}
+
+func TestCodecKeyRotation(t *testing.T) {
+	old, _ := timeflake.NewCodec(mustKey("000102030405060708090a0b0c0d0e0f"))
+	rotated, err := timeflake.NewCodec(
+		mustKey("ffeeddccbbaa99887766554433221100ffeeddccbbaa99887766554433221100"),
+		mustKey("000102030405060708090a0b0c0d0e0f"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tf, _ := timeflake.Random()
+	opaque, _ := old.Encrypt(tf)
+	back, err := rotated.DecryptKey(opaque, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Base62 != tf.Base62 {
+		t.Errorf("expected %s got %s", tf.Base62, back.Base62)
+	}
+
+	opaque, _ = rotated.Encrypt(tf)
+	if back, _ := rotated.Decrypt(opaque); back == nil || back.Base62 != tf.Base62 {
+		t.Error("IDs of the primary key should decrypt")
+	}
+}
+
+func TestCodecDecryptOnlyUsesPrimaryKey(t *testing.T) {
+	old, _ := timeflake.NewCodec(mustKey("000102030405060708090a0b0c0d0e0f"))
+	rotated, _ := timeflake.NewCodec(
+		mustKey("ffeeddccbbaa99887766554433221100"),
+		mustKey("000102030405060708090a0b0c0d0e0f"),
+	)
+
+	// The result does not depend on the time of the ID or the clock.
+	for i := 0; i < 100; i++ {
+		tf, _ := timeflake.Random()
+		opaque, _ := old.Encrypt(tf)
+		back, err := rotated.Decrypt(opaque)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := rotated.DecryptKey(opaque, 0)
+		if back.Base62 != want.Base62 {
+			t.Fatalf("Decrypt should use the primary key: %s != %s", back.Base62, want.Base62)
+		}
+	}
+}
+
+func TestCodecDecryptKey(t *testing.T) {
+	old, _ := timeflake.NewCodec(mustKey("000102030405060708090a0b0c0d0e0f"))
+	rotated, _ := timeflake.NewCodec(
+		mustKey("ffeeddccbbaa99887766554433221100"),
+		mustKey("000102030405060708090a0b0c0d0e0f"),
+	)
+
+	tf, _ := timeflake.Random()
+	opaque, _ := old.Encrypt(tf)
+	back, err := rotated.DecryptKey(opaque, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Base62 != tf.Base62 {
+		t.Errorf("expected %s got %s", tf.Base62, back.Base62)
+	}
+	for _, key := range []int{-1, 2} {
+		if _, err := rotated.DecryptKey(opaque, key); err == nil {
+			t.Errorf("key %d should be rejected", key)
+		}
+	}
+}
+
+func TestNewCodecRejectsInvalidKeys(t *testing.T) {
+	if _, err := timeflake.NewCodec([]byte("short")); err == nil {
+		t.Error("keys must be valid AES keys")
+	}
+}
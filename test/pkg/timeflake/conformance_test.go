@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// The corpus in testdata/vectors.json is shared with other implementations,
+// see testdata/gen_vectors.py for how it was created.
+type vector struct {
+	Int       string `json:"int"`
+	Hex       string `json:"hex"`
+	Base62    string `json:"base62"`
+	UUID      string `json:"uuid"`
+	Timestamp int64  `json:"timestamp"`
+	Random    string `json:"random"`
+}
+
+func loadVectors(t *testing.T) []vector {
+	b, err := ioutil.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus struct {
+		Vectors []vector `json:"vectors"`
+	}
+	if err := json.Unmarshal(b, &corpus); err != nil {
+		t.Fatal(err)
+	}
+	if len(corpus.Vectors) == 0 {
+		t.Fatal("corpus is empty")
+	}
+	return corpus.Vectors
+}
+
+func checkVector(t *testing.T, path string, v vector, tf *timeflake.Timeflake, err error) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%s(%s): %s", path, v.Hex, err)
+		return
+	}
+	if tf.Int.String() != v.Int {
+		t.Errorf("%s(%s): int %s != %s", path, v.Hex, tf.Int.String(), v.Int)
+	}
+	if tf.Hex != v.Hex {
+		t.Errorf("%s(%s): hex %s != %s", path, v.Hex, tf.Hex, v.Hex)
+	}
+	if tf.Base62 != v.Base62 {
+		t.Errorf("%s(%s): base62 %s != %s", path, v.Hex, tf.Base62, v.Base62)
+	}
+	if tf.UUID != v.UUID {
+		t.Errorf("%s(%s): uuid %s != %s", path, v.Hex, tf.UUID, v.UUID)
+	}
+	if ms := tf.Time().Unix()*1000 + int64(tf.Time().Nanosecond()/1e6); ms != v.Timestamp {
+		t.Errorf("%s(%s): timestamp %d != %d", path, v.Hex, ms, v.Timestamp)
+	}
+	if ms := tf.ID().Time(); !ms.Equal(tf.Time()) {
+		t.Errorf("%s(%s): ID time %s != %s", path, v.Hex, ms, tf.Time())
+	}
+	if tf.Rand() != v.Random {
+		t.Errorf("%s(%s): random %s != %s", path, v.Hex, tf.Rand(), v.Random)
+	}
+}
+
+func TestConformanceVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		tf, err := timeflake.FromHex(v.Hex)
+		checkVector(t, "FromHex", v, tf, err)
+
+		tf, err = timeflake.FromBase62(v.Base62)
+		checkVector(t, "FromBase62", v, tf, err)
+
+		b, _ := hex.DecodeString(v.Hex)
+		tf, err = timeflake.FromBytes(b)
+		checkVector(t, "FromBytes", v, tf, err)
+
+		for _, value := range []string{v.Hex, v.Base62, v.UUID} {
+			tf, err = timeflake.Parse(value)
+			checkVector(t, "Parse", v, tf, err)
+		}
+
+		// Decimal ints may have the length of another format, so they are
+		// decoded explicitly.
+		format, _ := timeflake.LookupFormat("int")
+		tf, err = format.Decode(v.Int)
+		checkVector(t, "int", v, tf, err)
+
+		if tf, err = timeflake.FromHex(v.Hex); err == nil {
+			tf, err = timeflake.FromULIDString(tf.ToULIDString())
+		}
+		checkVector(t, "FromULIDString", v, tf, err)
+
+		id, err := timeflake.ParseID(v.Base62)
+		if err != nil || id.String() != v.Base62 {
+			t.Errorf("ParseID(%s): %s %v", v.Base62, id, err)
+		}
+		if u := uuid.UUID(id); u.String() != v.UUID {
+			t.Errorf("ID(%s): uuid %s != %s", v.Hex, u, v.UUID)
+		}
+
+		switch v.Random {
+		case "0":
+			tf, err = timeflake.MinForTime(tf.Time())
+			checkVector(t, "MinForTime", v, tf, err)
+		case "1208925819614629174706175":
+			tf, err = timeflake.MaxForTime(tf.Time())
+			checkVector(t, "MaxForTime", v, tf, err)
+		}
+	}
+}
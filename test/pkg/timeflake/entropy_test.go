@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// countingReader returns consecutive Bytes and counts the reads.
+type countingReader struct {
+	mu    sync.Mutex
+	next  byte
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads++
+	for i := range p {
+		p[i] = r.next
+		r.next++
+	}
+	return len(p), nil
+}
+
+func (r *countingReader) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reads
+}
+
+func TestBufferedEntropyReadsInBlocks(t *testing.T) {
+	src := &countingReader{}
+	e := timeflake.NewBufferedEntropy(src, 100)
+	defer e.Close()
+
+	var got []byte
+	for i := 0; i < 10; i++ {
+		p := make([]byte, 10)
+		if _, err := io.ReadFull(e, p); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p...)
+	}
+	for i, b := range got {
+		if b != byte(i) {
+			t.Fatalf("Byte %d is %d, Bytes should be handed out in order and once", i, b)
+		}
+	}
+	// One block was read for the reads, at most two more are prefetched.
+	if n := src.count(); n < 1 || n > 3 {
+		t.Errorf("100 Bytes took %d reads from the source", n)
+	}
+}
+
+// wordReader returns consecutive big endian uint32 counters.
+type wordReader struct {
+	next uint32
+}
+
+func (r *wordReader) Read(p []byte) (int, error) {
+	for i := 0; i+4 <= len(p); i += 4 {
+		binary.BigEndian.PutUint32(p[i:], r.next)
+		r.next++
+	}
+	return len(p) - len(p)%4, nil
+}
+
+func TestBufferedEntropyConcurrentReadsDoNotShareBytes(t *testing.T) {
+	e := timeflake.NewBufferedEntropy(&wordReader{}, 64)
+	defer e.Close()
+
+	var mu sync.Mutex
+	seen := make(map[uint32]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				p := make([]byte, 4)
+				if _, err := e.Read(p); err != nil {
+					t.Error(err)
+					return
+				}
+				word := binary.BigEndian.Uint32(p)
+				mu.Lock()
+				if seen[word] {
+					t.Errorf("Bytes %x were handed out twice", p)
+				}
+				seen[word] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBufferedEntropyReseed(t *testing.T) {
+	src := &countingReader{}
+	e := timeflake.NewBufferedEntropy(src, 16)
+	defer e.Close()
+
+	p := make([]byte, 1)
+	e.Read(p)
+	before := src.count()
+	e.Reseed()
+	e.Read(p)
+	if int(p[0]) < before*16 {
+		t.Errorf("read after Reseed returned Byte %d from one of the %d blocks read before it", p[0], before)
+	}
+}
+
+func TestBufferedEntropyReportsSourceErrors(t *testing.T) {
+	e := timeflake.NewBufferedEntropy(bytes.NewReader(nil), 16)
+	defer e.Close()
+
+	_, err := e.Read(make([]byte, 10))
+	var gerr *customerr.GeneratorError
+	if !errors.As(err, &gerr) {
+		t.Errorf("expected a GeneratorError, got %v", err)
+	}
+}
+
+func TestBufferedEntropyClosed(t *testing.T) {
+	e := timeflake.NewBufferedEntropy(nil, 16)
+	e.Close()
+	if _, err := e.Read(make([]byte, 10)); !errors.Is(err, timeflake.ErrEntropyClosed) {
+		t.Errorf("expected ErrEntropyClosed, got %v", err)
+	}
+}
+
+func TestGeneratorWithBufferedEntropy(t *testing.T) {
+	e := timeflake.NewBufferedEntropy(nil, 4096)
+	defer e.Close()
+	g := timeflake.NewGenerator(timeflake.WithEntropy(e))
+	for i := 0; i < 1000; i++ {
+		if _, err := g.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEntropyCryptoRand(b *testing.B) {
+	p := make([]byte, 10)
+	for i := 0; i < b.N; i++ {
+		rand.Read(p)
+	}
+}
+
+func BenchmarkEntropyBuffered(b *testing.B) {
+	e := timeflake.NewBufferedEntropy(nil, 4096)
+	defer e.Close()
+	p := make([]byte, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Read(p)
+	}
+}
+
+func BenchmarkEntropyCryptoRandParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		p := make([]byte, 10)
+		for pb.Next() {
+			rand.Read(p)
+		}
+	})
+}
+
+func BenchmarkEntropyBufferedParallel(b *testing.B) {
+	e := timeflake.NewBufferedEntropy(nil, 4096)
+	defer e.Close()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		p := make([]byte, 10)
+		for pb.Next() {
+			e.Read(p)
+		}
+	})
+}
+
+func BenchmarkGeneratorCryptoRand(b *testing.B) {
+	g := timeflake.NewGenerator()
+	for i := 0; i < b.N; i++ {
+		g.Next()
+	}
+}
+
+func BenchmarkGeneratorBufferedEntropy(b *testing.B) {
+	e := timeflake.NewBufferedEntropy(nil, 4096)
+	defer e.Close()
+	g := timeflake.NewGenerator(timeflake.WithEntropy(e))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Next()
+	}
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+var fuzzSeeds = []string{
+	"02lVIoVLUfN6xUwLlnSRjj",
+	"0177487ec2f8d0a63f2785a9cadfc50f",
+	"0177487e-c2f8-d0a6-3f27-85a9cadfc50f",
+	"01ARZ3NDEKTSV4RRFFQ69G5FAV",
+	"1948581698531390905820074514793350415",
+	"0000000000000000000000",
+	"zzzzzzzzzzzzzzzzzzzzzz",
+	"ffffffffffffffffffffffffffffffffff",
+	"",
+	"-1",
+	"ö",
+}
+
+func isTypedError(err error) bool {
+	switch err.(type) {
+	case *customerr.ConversionError, *customerr.OutOfBoundsError, *customerr.UUIDError:
+		return true
+	}
+	return false
+}
+
+// checkRoundTrip formats tf in every encoding, parses it again and checks
+// that all of them result in the same ID.
+func checkRoundTrip(t *testing.T, input string, tf *timeflake.Timeflake) {
+	for _, f := range timeflake.Formats {
+		again, err := f.Decode(f.Encode(tf))
+		if err != nil {
+			t.Fatalf("%q: decoding %s %q failed: %s", input, f.Name, f.Encode(tf), err)
+		}
+		if !bytes.Equal(again.Bytes, tf.Bytes) {
+			t.Fatalf("%q: %s round trip returned %s instead of %s", input, f.Name, again.Hex, tf.Hex)
+		}
+	}
+	for _, s := range []string{tf.Base62, tf.Hex, tf.UUID, tf.ToULIDString()} {
+		again, err := timeflake.Parse(s)
+		if err != nil || again.ID() != tf.ID() {
+			t.Fatalf("%q: parsing %q does not agree with %s: %v", input, s, tf.Hex, err)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		tf, err := timeflake.Parse(input)
+		if err != nil {
+			if !isTypedError(err) {
+				t.Fatalf("%q: untyped error %T %s", input, err, err)
+			}
+			return
+		}
+		checkRoundTrip(t, input, tf)
+	})
+}
+
+func FuzzFromHex(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		tf, err := timeflake.FromHex(input)
+		if err != nil {
+			if !isTypedError(err) {
+				t.Fatalf("%q: untyped error %T %s", input, err, err)
+			}
+			return
+		}
+		checkRoundTrip(t, input, tf)
+	})
+}
+
+func FuzzFromBase62(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		tf, err := timeflake.FromBase62(input)
+		if err != nil {
+			if !isTypedError(err) {
+				t.Fatalf("%q: untyped error %T %s", input, err, err)
+			}
+			return
+		}
+		checkRoundTrip(t, input, tf)
+	})
+}
+
+func FuzzFromBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, 16))
+	f.Add(bytes.Repeat([]byte{0xff}, 16))
+	f.Add([]byte{1, 119, 72, 126, 194, 248, 208, 166, 63, 39, 133, 169, 202, 223, 197, 15})
+	f.Fuzz(func(t *testing.T, input []byte) {
+		tf, err := timeflake.FromBytes(input)
+		if err != nil {
+			if !isTypedError(err) {
+				t.Fatalf("%x: untyped error %T %s", input, err, err)
+			}
+			return
+		}
+		if !bytes.Equal(tf.Bytes, input) {
+			t.Fatalf("%x: bytes changed to %x", input, tf.Bytes)
+		}
+		checkRoundTrip(t, tf.Hex, tf)
+	})
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestGeneratorIsMonotonic(t *testing.T) {
+	g := timeflake.NewGenerator()
+	prev, _ := g.Next()
+	for i := 0; i < 10000; i++ {
+		f, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev.Int.Cmp(&f.Int) >= 0 {
+			t.Fatalf("timeflakes are not increasing: %s >= %s", prev.Hex, f.Hex)
+		}
+		prev = f
+	}
+}
+
+func TestGeneratorIncrementsWithinMillisecond(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }))
+
+	a, _ := g.Next()
+	b, _ := g.Next()
+	if new(big.Int).Sub(b.BigRand(), a.BigRand()).Int64() != 1 {
+		t.Errorf("random part should be incremented: %s, %s", a.Hex, b.Hex)
+	}
+}
+
+func TestGeneratorSurvivesClockGoingBackwards(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }))
+
+	a, _ := g.Next()
+	now = now.Add(-time.Hour)
+	b, _ := g.Next()
+	if a.Int.Cmp(&b.Int) >= 0 || !b.Time().Equal(a.Time()) {
+		t.Errorf("timeflake should keep the last timestamp: %s, %s", a.Hex, b.Hex)
+	}
+}
+
+func TestGeneratorBorrowsNextMillisecondOnOverflow(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	max := bytes.Repeat([]byte{0xff}, 10)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(append(max, make([]byte, 10)...))),
+	)
+
+	a, _ := g.Next()
+	b, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Time().Equal(a.Time().Add(time.Millisecond)) {
+		t.Errorf("timeflake should use the next millisecond: %s, %s", a.Hex, b.Hex)
+	}
+}
+
+func TestGeneratorFailsWithoutEntropy(t *testing.T) {
+	g := timeflake.NewGenerator(timeflake.WithEntropy(bytes.NewReader(nil)))
+	if _, err := g.Next(); err == nil {
+		t.Error("generator should fail without entropy")
+	}
+}
+
+func TestGeneratorIsConcurrencySafe(t *testing.T) {
+	g := timeflake.NewGenerator()
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				f, err := g.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[f.Base62] {
+					t.Errorf("duplicate timeflake %s", f.Base62)
+				}
+				seen[f.Base62] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// stalledGenerator returns a Generator whose clock is frozen and whose first
+// Timeflake exhausts the random part of the millisecond, so the next one has
+// to wait for the clock.
+func stalledGenerator(t *testing.T, clock func() time.Time) *timeflake.Generator {
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(clock),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 20))),
+		timeflake.WithClockWait(),
+	)
+	if _, err := g.Next(); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestNextContextGivesUpWaitingForClock(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := stalledGenerator(t, func() time.Time { return now })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := g.NextContext(ctx)
+	var gerr *customerr.GeneratorError
+	if !errors.As(err, &gerr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a GeneratorError wrapping the deadline, got %v", err)
+	}
+}
+
+func TestNextContextWaitsForClock(t *testing.T) {
+	clock := &lockedClock{now: time.Unix(1611829003, 0)}
+	g := stalledGenerator(t, clock.Now)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		clock.Add(time.Millisecond)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f, err := g.NextContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Time().Equal(clock.Now().UTC()) {
+		t.Errorf("timeflake should have the time of the clock, got %s", f.Time())
+	}
+}
+
+func TestNextContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := timeflake.NewGenerator().NextContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err := timeflake.NewGenerator().NextNContext(ctx, 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGeneratorWithoutClockWaitRunsAhead(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 20))),
+	)
+	g.Next()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	f, err := g.NextContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Time().Equal(now.Add(time.Millisecond).UTC()) {
+		t.Errorf("timeflake should borrow the next millisecond, got %s", f.Time())
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestHLCIssuesIDsAfterObservedOnes(t *testing.T) {
+	local := time.Unix(1611829003, 0)
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }))
+
+	// A node whose clock is 500ms ahead.
+	remote, _ := timeflake.DefaultLayout.ForTime(local.Add(500*time.Millisecond), big.NewInt(12345))
+	if err := h.Observe(remote); err != nil {
+		t.Fatal(err)
+	}
+	f, err := h.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ID().Int().Cmp(remote.Int()) <= 0 {
+		t.Errorf("ID %s is not greater than the observed %s", f.ID(), remote)
+	}
+	if !f.Time().Equal(remote.Time()) {
+		t.Errorf("logical time should be %s, got %s", remote.Time(), f.Time())
+	}
+
+	// Once the local clock passes the logical time, it is followed again.
+	local = local.Add(time.Second)
+	f, _ = h.Next()
+	if !f.Time().Equal(local.UTC()) {
+		t.Errorf("HLC should follow the clock again, got %s", f.Time())
+	}
+}
+
+func TestHLCIgnoresObservedIDsFromThePast(t *testing.T) {
+	local := time.Unix(1611829003, 0)
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }))
+
+	a, _ := h.Next()
+	old, _ := timeflake.DefaultLayout.ForTime(local.Add(-time.Minute), big.NewInt(1))
+	if err := h.Observe(old); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := h.Next()
+	if a.Int.Cmp(&b.Int) >= 0 || !b.Time().Equal(local.UTC()) {
+		t.Errorf("an old observed ID should not change the logical time: %s, %s", a.Hex, b.Hex)
+	}
+}
+
+func TestHLCRefusesToExceedMaxDrift(t *testing.T) {
+	local := time.Unix(1611829003, 0)
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }))
+
+	remote, _ := timeflake.DefaultLayout.ForTime(local.Add(2*time.Second), big.NewInt(1))
+	err := h.Observe(remote)
+	var oob *customerr.OutOfBoundsError
+	if !errors.As(err, &oob) {
+		t.Fatalf("expected an OutOfBoundsError, got %v", err)
+	}
+	f, _ := h.Next()
+	if !f.Time().Equal(local.UTC()) {
+		t.Errorf("a rejected ID should not move the logical time, got %s", f.Time())
+	}
+}
+
+func TestHLCObservesUUIDv7(t *testing.T) {
+	local := time.Unix(1611829003, 0)
+	remote := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return local }), timeflake.WithUUIDv7())
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }), timeflake.WithUUIDv7())
+
+	for i := 0; i < 100; i++ {
+		r, _ := remote.Next()
+		if err := h.Observe(r.ID()); err != nil {
+			t.Fatal(err)
+		}
+		f, err := h.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Int.Cmp(&r.Int) <= 0 || !f.IsUUIDv7() {
+			t.Fatalf("ID %s should be a UUIDv7 greater than %s", f.UUID, r.UUID)
+		}
+	}
+}
+
+func TestHLCWithUUIDv7ObservesOtherIDs(t *testing.T) {
+	local := time.Date(2021, 1, 28, 10, 16, 43, 0, time.UTC)
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }), timeflake.WithUUIDv7())
+
+	observed, _ := timeflake.FromHex("0177487ec2f8d0a63f2785a9cadfc50f")
+	if observed.IsUUIDv7() {
+		t.Fatal("the observed ID should not be a UUIDv7")
+	}
+	if err := h.Observe(observed.ID()); err != nil {
+		t.Fatal(err)
+	}
+	f, err := h.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Int.Cmp(&observed.Int) <= 0 || !f.IsUUIDv7() {
+		t.Errorf("ID %s should be a UUIDv7 greater than %s", f.Hex, observed.Hex)
+	}
+}
+
+func TestHLCObservesOtherLayouts(t *testing.T) {
+	local := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h := timeflake.NewHLC(time.Second, timeflake.WithClock(func() time.Time { return local }), timeflake.WithLayout(compact))
+
+	remote, _ := compact.ForTime(local.Add(300*time.Millisecond), big.NewInt(7))
+	if err := h.Observe(remote); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := h.NextID()
+	if got := compact.Time(id); !got.Equal(local.Add(300 * time.Millisecond)) {
+		t.Errorf("logical time should be %s, got %s", local.Add(300*time.Millisecond), got)
+	}
+
+	wide, _ := timeflake.DefaultLayout.ForTime(local, big.NewInt(1))
+	if err := h.Observe(wide); err == nil {
+		t.Error("IDs wider than the layout should be rejected")
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestIDEncodings(t *testing.T) {
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	id := tf.ID()
+
+	if id.Base62() != tf.Base62 || id.String() != tf.Base62 {
+		t.Errorf("base62 is not correct %s", id.Base62())
+	}
+	if id.Hex() != tf.Hex {
+		t.Errorf("hex is not correct %s", id.Hex())
+	}
+	if id.UUIDString() != tf.UUID {
+		t.Errorf("uuid is not correct %s", id.UUIDString())
+	}
+	if id.Int().Cmp(&tf.Int) != 0 {
+		t.Errorf("int is not correct %s", id.Int())
+	}
+	if id.Random().Cmp(tf.BigRand()) != 0 {
+		t.Errorf("random part is not correct %s", id.Random())
+	}
+	if !id.Time().Equal(tf.Time()) {
+		t.Errorf("time is not correct %s", id.Time())
+	}
+	if id.Timeflake().Base62 != tf.Base62 {
+		t.Errorf("timeflake is not correct %s", id.Timeflake().Base62)
+	}
+}
+
+func TestIDIsImmutable(t *testing.T) {
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	id := tf.ID()
+
+	b := id.Bytes()
+	b[0] = 0xff
+	id.Int().SetInt64(0)
+	tf.Bytes[0] = 0xff
+
+	if id.Base62() != "02lVIoVLUfN6xUwLlnSRjj" {
+		t.Errorf("ID should not change %s", id)
+	}
+}
+
+func TestIDFormat(t *testing.T) {
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	id := tf.ID()
+
+	for format, expected := range map[string]string{
+		"%s":  "02lVIoVLUfN6xUwLlnSRjj",
+		"%v":  "02lVIoVLUfN6xUwLlnSRjj",
+		"%q":  `"02lVIoVLUfN6xUwLlnSRjj"`,
+		"%x":  "0177487ec2f8d0a63f2785a9cadfc50f",
+		"%X":  "0177487EC2F8D0A63F2785A9CADFC50F",
+		"%d":  "1948581698531390905820074514793350415",
+		"%+v": "ts=1611829003000 rand=985318938706034770822415 int=1948581698531390905820074514793350415 hex=0177487ec2f8d0a63f2785a9cadfc50f base62=02lVIoVLUfN6xUwLlnSRjj uuid=0177487e-c2f8-d0a6-3f27-85a9cadfc50f",
+	} {
+		if s := fmt.Sprintf(format, id); s != expected {
+			t.Errorf("%s: expected %s got %s", format, expected, s)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+var compact = timeflake.Layout{
+	Name:          "c96",
+	Epoch:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	TimestampBits: 40,
+	RandomBits:    56,
+}
+
+func TestLayoutGeneratorRecoversTime(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 15, 123000000, time.UTC)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithLayout(compact))
+
+	id, err := g.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := compact.Time(id); !got.Equal(now) {
+		t.Errorf("Time() = %s, want %s", got, now)
+	}
+	if id.Int().BitLen() > 96 {
+		t.Errorf("ID of a 96 bit layout has %d bits", id.Int().BitLen())
+	}
+	if got := compact.Random(id); got.Cmp(new(big.Int).Lsh(big.NewInt(1), 56)) >= 0 {
+		t.Errorf("random part %s does not fit into 56 bits", got)
+	}
+}
+
+func TestLayoutGeneratorIsMonotonic(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 15, 0, time.UTC)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithLayout(compact))
+
+	prev, _ := g.NextID()
+	for i := 0; i < 1000; i++ {
+		id, err := g.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(prev[:], id[:]) >= 0 {
+			t.Fatalf("IDs are not increasing: %x >= %x", prev, id)
+		}
+		prev = id
+	}
+}
+
+func TestLayoutRejectsTimeBeforeEpoch(t *testing.T) {
+	now := compact.Epoch.Add(-time.Millisecond)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithLayout(compact))
+
+	_, err := g.NextID()
+	var oob *customerr.OutOfBoundsError
+	if !errors.As(err, &oob) {
+		t.Errorf("expected an OutOfBoundsError, got %v", err)
+	}
+}
+
+func TestLayoutEncodeParseRoundTrip(t *testing.T) {
+	id, err := compact.ForTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := compact.Encode(id)
+	if !strings.HasPrefix(s, "c96.kStW:") || len(s) != len("c96.kStW:")+17 {
+		t.Errorf("unexpected encoding %q", s)
+	}
+	got, err := compact.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != id {
+		t.Errorf("Parse(%q) = %x, want %x", s, got, id)
+	}
+
+	b := compact.Bytes(id)
+	if len(b) != 12 {
+		t.Errorf("Bytes() has %d Bytes, want 12", len(b))
+	}
+	got, err = compact.FromBytes(b)
+	if err != nil || got != id {
+		t.Errorf("FromBytes(%x) = %x, %v", b, got, err)
+	}
+}
+
+func TestLayoutParseRejectsOtherLayouts(t *testing.T) {
+	other := compact
+	other.Name = "other"
+	id, _ := other.ForTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), big.NewInt(1))
+
+	for _, value := range []string{
+		other.Encode(id),
+		timeflake.DefaultLayout.Encode(id),
+		strings.TrimPrefix(compact.Encode(id), "c96.kStW:"),
+		strings.Replace(compact.Encode(id), ".kStW:", ":", 1),
+	} {
+		if _, err := compact.Parse(value); err == nil {
+			t.Errorf("Parse(%q) should fail", value)
+		}
+	}
+	if _, err := timeflake.DefaultLayout.Parse(compact.Encode(id)); err == nil {
+		t.Error("DefaultLayout should reject IDs of a named layout")
+	}
+}
+
+func TestLayoutParseRejectsLayoutsWithTheSameName(t *testing.T) {
+	epoch := compact
+	epoch.Epoch = epoch.Epoch.Add(time.Hour)
+	widths := compact
+	widths.TimestampBits, widths.RandomBits = 44, 52
+	fraction := compact
+	fraction.FractionBits = 8
+
+	id, _ := compact.ForTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), big.NewInt(1))
+	for _, other := range []timeflake.Layout{epoch, widths, fraction} {
+		if _, err := other.Parse(compact.Encode(id)); err == nil {
+			t.Errorf("layout %+v should reject IDs of %+v", other, compact)
+		}
+		if _, err := compact.Parse(other.Encode(id)); err == nil {
+			t.Errorf("layout %+v should reject IDs of %+v", compact, other)
+		}
+	}
+}
+
+func TestLayoutParseRejectsValuesWiderThanLayout(t *testing.T) {
+	if _, err := compact.Parse("c96.kStW:" + strings.Repeat("z", 17)); err == nil {
+		t.Error("Parse should reject values wider than 96 bits")
+	}
+}
+
+func TestDefaultLayoutMatchesTimeflake(t *testing.T) {
+	now := time.Date(2021, 1, 28, 10, 16, 43, 5000000, time.UTC)
+	random := big.NewInt(1234567890)
+	want, err := timeflake.MinForTime(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ = timeflake.FromBytes(append(want.Bytes[:6:6], new(big.Int).Or(want.BigRand(), random).FillBytes(make([]byte, 10))...))
+
+	id, err := timeflake.DefaultLayout.ForTime(now, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(id[:], want.Bytes) {
+		t.Errorf("ForTime() = %x, want %s", id, want.Hex)
+	}
+	if got := timeflake.DefaultLayout.Encode(id); got != want.Base62 {
+		t.Errorf("Encode() = %s, want %s", got, want.Base62)
+	}
+	if got := timeflake.DefaultLayout.Time(id); !got.Equal(now) {
+		t.Errorf("Time() = %s, want %s", got, now)
+	}
+}
+
+func TestLayoutValidation(t *testing.T) {
+	for _, l := range []timeflake.Layout{
+		{Epoch: compact.Epoch, TimestampBits: 40, RandomBits: 56},
+		{Name: "wide", TimestampBits: 49, RandomBits: 79},
+		{Name: "big", TimestampBits: 48, RandomBits: 81},
+		{Name: "a:b", TimestampBits: 40, RandomBits: 56},
+	} {
+		if _, err := l.ForTime(time.Now(), big.NewInt(0)); err == nil {
+			t.Errorf("layout %+v should be invalid", l)
+		}
+	}
+
+	var cerr *customerr.ConfigError
+	g := timeflake.NewGenerator(timeflake.WithLayout(compact), timeflake.WithUUIDv7())
+	if _, err := g.NextID(); !errors.As(err, &cerr) {
+		t.Errorf("UUIDv7 should require the default layout, got %v", err)
+	}
+	g = timeflake.NewGenerator(timeflake.WithLayout(timeflake.Layout{Name: "wide", TimestampBits: 49, RandomBits: 79}))
+	if _, err := g.NextID(); !errors.As(err, &cerr) {
+		t.Errorf("expected a ConfigError for an invalid layout, got %v", err)
+	}
+}
+
+func TestLayoutGeneratorRefusesTimeflakes(t *testing.T) {
+	g := timeflake.NewGenerator(timeflake.WithLayout(compact))
+	var cerr *customerr.ConfigError
+	if f, err := g.Next(); f != nil || !errors.As(err, &cerr) {
+		t.Errorf("IDs of a custom layout must not be returned as Timeflakes, got %v", err)
+	}
+	if _, err := g.NextN(2); !errors.As(err, &cerr) {
+		t.Errorf("IDs of a custom layout must not be returned as Timeflakes, got %v", err)
+	}
+
+	g = timeflake.NewGenerator(timeflake.WithLayout(timeflake.SubMillisLayout))
+	if _, err := g.Next(); err != nil {
+		t.Errorf("IDs of SubMillisLayout are Timeflakes: %s", err)
+	}
+}
+
+func TestSubMillisLayoutOrdersWithinMillisecond(t *testing.T) {
+	base := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	clock := base
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return clock }), timeflake.WithLayout(timeflake.SubMillisLayout))
+
+	var prev timeflake.ID
+	for i := 0; i < 10; i++ {
+		clock = base.Add(time.Duration(i) * 50 * time.Microsecond)
+		f, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := f.ID()
+		if i > 0 && bytes.Compare(prev[:], id[:]) >= 0 {
+			t.Fatalf("IDs are not increasing: %x >= %x", prev, id)
+		}
+		prev = id
+
+		got := timeflake.SubMillisLayout.Time(id)
+		if d := clock.Sub(got); d < 0 || d > 250*time.Nanosecond {
+			t.Errorf("Time() = %s, want within 244ns before %s", got, clock)
+		}
+		if !f.Time().Equal(base) {
+			t.Errorf("the Timeflake millisecond should stay %s, got %s", base, f.Time())
+		}
+	}
+}
+
+func TestSubMillisLayoutFractionDecidesOrder(t *testing.T) {
+	ms := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	late, _ := timeflake.SubMillisLayout.ForTime(ms.Add(900*time.Microsecond), big.NewInt(0))
+	early, _ := timeflake.SubMillisLayout.ForTime(ms.Add(100*time.Microsecond), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 68), big.NewInt(1)))
+	if bytes.Compare(early[:], late[:]) >= 0 {
+		t.Errorf("the fraction should sort before the random part: %x >= %x", early, late)
+	}
+	if _, err := timeflake.SubMillisLayout.ForTime(ms, new(big.Int).Lsh(big.NewInt(1), 68)); err == nil {
+		t.Error("random parts wider than 68 bits should be rejected")
+	}
+}
+
+func TestSubMillisLayoutBorrowsNextFraction(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	full := bytes.Repeat([]byte{0xff}, 9)
+	g := timeflake.NewGenerator(
+		timeflake.WithClock(func() time.Time { return now }),
+		timeflake.WithEntropy(bytes.NewReader(append(full, full...))),
+		timeflake.WithLayout(timeflake.SubMillisLayout),
+	)
+	a, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := timeflake.SubMillisLayout.Time(b.ID()).Sub(timeflake.SubMillisLayout.Time(a.ID())); d <= 0 || d > 250*time.Nanosecond {
+		t.Errorf("an exhausted fraction should borrow the next one, moved %s", d)
+	}
+}
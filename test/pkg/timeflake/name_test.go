@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// The expected values were computed with Python's hmac module.
+var nameVectors = []struct {
+	namespace string
+	name      string
+	time      time.Time
+	hex       string
+}{
+	{"users", "alice@example.com", time.Unix(1611829003, 0), "0177487ec2f8414ef2bc3d3e8b4908f2"},
+	{"users", "bob@example.com", time.Unix(1611829003, 0), "0177487ec2f80510c0fb5b9de3411eb0"},
+	{"orders", "alice@example.com", time.Unix(1611829003, 0), "0177487ec2f8d2e7edfcddaa1a83f900"},
+	{"", "", time.Unix(0, 0), "000000000000b613679a0814d9ec772f"},
+}
+
+func TestFromName(t *testing.T) {
+	for _, v := range nameVectors {
+		tf, err := timeflake.FromName([]byte(v.namespace), []byte(v.name), v.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tf.Hex != v.hex {
+			t.Errorf("%s/%s: expected %s got %s", v.namespace, v.name, v.hex, tf.Hex)
+		}
+
+		again, _ := timeflake.FromName([]byte(v.namespace), []byte(v.name), v.time)
+		if again.Hex != tf.Hex {
+			t.Errorf("%s/%s: timeflake is not deterministic", v.namespace, v.name)
+		}
+	}
+}
+
+func TestFromNameKeepsTime(t *testing.T) {
+	now := time.Unix(1611829003, 123456789)
+	tf, err := timeflake.FromName([]byte("users"), []byte("alice@example.com"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tf.Time().Equal(now.Truncate(time.Millisecond)) {
+		t.Errorf("expected %s got %s", now.Truncate(time.Millisecond), tf.Time())
+	}
+
+	later, _ := timeflake.FromName([]byte("users"), []byte("alice@example.com"), now.Add(time.Millisecond))
+	if later.Int.Cmp(&tf.Int) <= 0 {
+		t.Error("a later time should result in a greater timeflake")
+	}
+}
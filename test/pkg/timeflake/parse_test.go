@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestParseDetectsFormat(t *testing.T) {
+	for _, value := range []string{
+		"02lVIoVLUfN6xUwLlnSRjj",
+		"0177487ec2f8d0a63f2785a9cadfc50f",
+		"0177487EC2F8D0A63F2785A9CADFC50F",
+		"0177487e-c2f8-d0a6-3f27-85a9cadfc50f",
+		"1948581698531390905820074514793350415",
+	} {
+		tf, err := timeflake.Parse(value)
+		if err != nil {
+			t.Errorf("parsing '%s' failed: %s", value, err)
+			continue
+		}
+		if tf.Base62 != "02lVIoVLUfN6xUwLlnSRjj" {
+			t.Errorf("parsing '%s' returned %s", value, tf.Base62)
+		}
+	}
+}
+
+func TestParseAcceptsFormattedIDs(t *testing.T) {
+	tf, _ := timeflake.Random()
+	for _, verb := range []string{"%s", "%x", "%X", "%d"} {
+		value := fmt.Sprintf(verb, tf.ID())
+		parsed, err := timeflake.Parse(value)
+		if err != nil {
+			t.Errorf("parsing %s output '%s' failed: %s", verb, value, err)
+		} else if parsed.ID() != tf.ID() {
+			t.Errorf("parsing %s output '%s' returned %s", verb, value, parsed.Base62)
+		}
+	}
+}
+
+func TestFormatsEncodeBytes(t *testing.T) {
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	// Encoders must not rely on the other fields, which callers can change.
+	tf.Base62, tf.Hex, tf.UUID = "", "", ""
+	tf.Int.SetInt64(0)
+	for _, f := range timeflake.Formats {
+		parsed, err := f.Decode(f.Encode(tf))
+		if err != nil || parsed.Base62 != "02lVIoVLUfN6xUwLlnSRjj" {
+			t.Errorf("format %s does not encode the bytes: %v", f.Name, err)
+		}
+	}
+}
+
+func TestParseKeepsLeadingZeros(t *testing.T) {
+	tf, err := timeflake.Parse("0000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tf.Hex != "00000000000000000000000000000001" {
+		t.Errorf("timeflake HEX is not correct. %s", tf.Hex)
+	}
+}
+
+func TestParseRejectsInvalidInput(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"02lVIoVLUfN6xUwLlnSRj-",
+		"0177487ec2f8d0a63f2785a9cadfc50g",
+		"zzzzzzzzzzzzzzzzzzzzzz",
+		"0177487e-c2f8-d0a6-3f27-85a9cadfc5xx",
+		"-1",
+	} {
+		_, err := timeflake.Parse(value)
+		switch err.(type) {
+		case *customerr.ConversionError, *customerr.OutOfBoundsError, *customerr.UUIDError:
+		default:
+			t.Errorf("parsing '%s' should fail with a typed error, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+type lockedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *lockedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *lockedClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func waitForPool(t *testing.T, p *timeflake.Pool, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool has %d timeflakes, want %d", p.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolRefillsToHighWatermark(t *testing.T) {
+	p := timeflake.NewPool(context.Background(), timeflake.NewGenerator(), timeflake.WithWatermarks(8, 32))
+	defer p.Close()
+	waitForPool(t, p, 32)
+
+	for i := 0; i < 30; i++ {
+		if _, err := p.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitForPool(t, p, 32)
+}
+
+func TestPoolIDsAreUnique(t *testing.T) {
+	p := timeflake.NewPool(context.Background(), timeflake.NewGenerator(), timeflake.WithWatermarks(16, 64))
+	defer p.Close()
+
+	var mu sync.Mutex
+	seen := make(map[timeflake.ID]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				f, err := p.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[f.ID()] {
+					t.Errorf("duplicate %s", f.Base62)
+				}
+				seen[f.ID()] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPoolDropsStaleIDs(t *testing.T) {
+	clock := &lockedClock{now: time.Unix(1611829003, 0)}
+	g := timeflake.NewGenerator(timeflake.WithClock(clock.Now))
+	p := timeflake.NewPool(context.Background(), g, timeflake.WithWatermarks(1, 16), timeflake.WithMaxLag(time.Hour))
+	defer p.Close()
+	waitForPool(t, p, 16)
+
+	clock.Add(2 * time.Hour)
+	f, err := p.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Time().Equal(clock.Now().UTC()) {
+		t.Errorf("pool returned a timeflake of %s, older than the maximum lag", f.Time())
+	}
+}
+
+func TestPoolFallsBackWhenEmpty(t *testing.T) {
+	p := timeflake.NewPool(context.Background(), timeflake.NewGenerator(), timeflake.WithWatermarks(1, 4))
+	waitForPool(t, p, 4)
+	p.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := p.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.Len() != 0 {
+		t.Errorf("a closed pool should not be refilled, has %d", p.Len())
+	}
+}
+
+func TestPoolStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := timeflake.NewPool(ctx, timeflake.NewGenerator())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pool did not stop after its context was cancelled")
+	}
+}
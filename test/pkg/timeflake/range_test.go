@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestMinAndMaxForTime(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	min, err := timeflake.MinForTime(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	max, err := timeflake.MaxForTime(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if min.Hex != "0177487ec2f800000000000000000000" {
+		t.Errorf("min timeflake is not correct. %s", min.Hex)
+	}
+	if max.Hex != "0177487ec2f8ffffffffffffffffffff" {
+		t.Errorf("max timeflake is not correct. %s", max.Hex)
+	}
+
+	tf, _ := timeflake.FromHex("0177487ec2f8d0a63f2785a9cadfc50f")
+	if min.Int.Cmp(&tf.Int) > 0 || max.Int.Cmp(&tf.Int) < 0 {
+		t.Error("timeflake of the same millisecond must be within the bounds")
+	}
+	if !min.Time().Equal(now) || !max.Time().Equal(now) {
+		t.Errorf("bounds must keep the time. %s, %s", min.Time(), max.Time())
+	}
+}
+
+func TestMinForTimeKeepsLeadingZeros(t *testing.T) {
+	tf, err := timeflake.MinForTime(time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tf.Bytes) != 16 {
+		t.Errorf("timeflake must be 16 Bytes %d", len(tf.Bytes))
+	}
+	if tf.UUID != "00000000-0000-0000-0000-000000000000" {
+		t.Errorf("timeflake UUID is not correct. %s", tf.UUID)
+	}
+}
+
+func TestMinForTimeOutOfBounds(t *testing.T) {
+	if _, err := timeflake.MinForTime(time.Unix(-1, 0)); err == nil {
+		t.Error("times before the Unix epoch must fail")
+	}
+	if _, err := timeflake.MaxForTime(time.Unix(281474976711, 0)); err == nil {
+		t.Error("times after the max timestamp must fail")
+	}
+}
+
+func TestRangeForExcludesTo(t *testing.T) {
+	from := time.Unix(1611829003, 0)
+	min, max, err := timeflake.RangeFor(from, from.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min.Hex != "0177487ec2f800000000000000000000" || max.Hex != "0177487ec6dfffffffffffffffffffff" {
+		t.Errorf("range is not correct %s, %s", min.Hex, max.Hex)
+	}
+
+	if _, _, err := timeflake.RangeFor(from, from); err == nil {
+		t.Error("an empty window should be rejected")
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Run the benchmarks with go test -bench Parallel -cpu 1,2,4,8 to compare
+// how the generators scale.
+
+func newSharded(t testing.TB, n int, opts ...timeflake.Option) *timeflake.ShardedGenerator {
+	s, err := timeflake.NewShardedGenerator(n, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestShardedGeneratorIDsAreUnique(t *testing.T) {
+	s := newSharded(t, 8)
+
+	var mu sync.Mutex
+	seen := make(map[timeflake.ID]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 64; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]timeflake.ID, 0, 500)
+			for i := 0; i < 500; i++ {
+				f, err := s.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids = append(ids, f.ID())
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate %s", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestShardedGeneratorShardsAreMonotonic(t *testing.T) {
+	s := newSharded(t, 4)
+
+	var wg sync.WaitGroup
+	// One worker per shard, competing with callers of Next.
+	for i := 0; i < s.Len(); i++ {
+		wg.Add(2)
+		go func(g *timeflake.Generator) {
+			defer wg.Done()
+			prev, _ := g.Next()
+			for j := 0; j < 1000; j++ {
+				f, err := g.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if prev.Int.Cmp(&f.Int) >= 0 {
+					t.Errorf("shard is not monotonic: %s >= %s", prev.Hex, f.Hex)
+					return
+				}
+				prev = f
+			}
+		}(s.Shard(i))
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if _, err := s.Next(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestShardedGeneratorEncodesShard(t *testing.T) {
+	s := newSharded(t, 4)
+	for i := 0; i < s.Len(); i++ {
+		f, err := s.Shard(i).Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := int(f.Bytes[6] >> 6); got != i {
+			t.Errorf("top random bits of shard %d hold %d", i, got)
+		}
+
+		ids, err := s.Shard(i).NewBatch(1000, timeflake.RandomGaps(16))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertAscending(t, ids)
+		for _, id := range ids {
+			if got := int(id[6] >> 6); got != i {
+				t.Fatalf("batch of shard %d left it: %x", i, id)
+			}
+		}
+	}
+}
+
+func TestShardedGeneratorUUIDv7(t *testing.T) {
+	s := newSharded(t, 4, timeflake.WithUUIDv7())
+	for i := 0; i < s.Len(); i++ {
+		f, err := s.Shard(i).Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.IsUUIDv7() {
+			t.Errorf("shard %d issued %s, not a UUIDv7", i, f.UUID)
+		}
+		if got := int(f.Bytes[6] >> 2 & 3); got != i {
+			t.Errorf("top random bits of shard %d hold %d", i, got)
+		}
+	}
+}
+
+func TestNewShardedGeneratorValidates(t *testing.T) {
+	s := newSharded(t, 0)
+	if s.Len() != runtime.GOMAXPROCS(0) {
+		t.Errorf("expected GOMAXPROCS shards got %d", s.Len())
+	}
+	for _, n := range []int{-1, timeflake.MaxShards + 1} {
+		if _, err := timeflake.NewShardedGenerator(n); err == nil {
+			t.Errorf("%d shards should be rejected", n)
+		}
+	}
+
+	narrow := timeflake.Layout{Name: "narrow", TimestampBits: 48, RandomBits: 2}
+	if _, err := timeflake.NewShardedGenerator(4, timeflake.WithLayout(narrow)); err == nil {
+		t.Error("shards without random bits should be rejected")
+	}
+	if _, err := timeflake.NewShardedGenerator(4, timeflake.WithLayout(timeflake.Layout{})); err == nil {
+		t.Error("invalid layouts should be rejected")
+	}
+}
+
+func BenchmarkGeneratorParallel(b *testing.B) {
+	g := timeflake.NewGenerator()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Next()
+		}
+	})
+}
+
+func BenchmarkShardedGeneratorParallel(b *testing.B) {
+	s := newSharded(b, 0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Next()
+		}
+	})
+}
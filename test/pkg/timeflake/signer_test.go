@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	s, err := timeflake.NewSigner(8, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+
+	// The tag was computed with Python's hmac module.
+	token := s.Sign(tf)
+	if token != "02lVIoVLUfN6xUwLlnSRjj.kQFvgyVH9iM" {
+		t.Errorf("token is not correct %s", token)
+	}
+
+	verified, err := s.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Base62 != tf.Base62 {
+		t.Errorf("expected %s got %s", tf.Base62, verified.Base62)
+	}
+}
+
+func TestVerifyRejectsForgedTokens(t *testing.T) {
+	s, _ := timeflake.NewSigner(8, []byte("secret"))
+	other, _ := timeflake.NewSigner(8, []byte("other"))
+	tf, _ := timeflake.Random()
+
+	for token, expected := range map[string]error{
+		other.Sign(tf):                                 timeflake.ErrInvalidSignature,
+		strings.Replace(s.Sign(tf), ".", "x", 1):       timeflake.ErrMalformedToken,
+		tf.Base62 + ".AAAA":                            timeflake.ErrMalformedToken,
+		tf.Base62 + ".!!!!!!!!!!!":                     timeflake.ErrMalformedToken,
+		"invalid." + strings.Split(s.Sign(tf), ".")[1]: timeflake.ErrMalformedToken,
+	} {
+		_, err := s.Verify(token)
+		if _, ok := err.(*customerr.TokenError); !ok {
+			t.Errorf("%s: expected a token error got %v", token, err)
+		}
+		if !errors.Is(err, expected) {
+			t.Errorf("%s: expected %v got %v", token, expected, err)
+		}
+	}
+}
+
+func TestVerifyOnlyAcceptsBase62(t *testing.T) {
+	s, _ := timeflake.NewSigner(8, []byte("secret"))
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	tag := strings.Split(s.Sign(tf), ".")[1]
+
+	for _, id := range []string{tf.Hex, tf.UUID, tf.ToULIDString(), tf.Int.String(), "2lVIoVLUfN6xUwLlnSRjj"} {
+		if _, err := s.Verify(id + "." + tag); !errors.Is(err, timeflake.ErrMalformedToken) {
+			t.Errorf("%s: expected %v got %v", id, timeflake.ErrMalformedToken, err)
+		}
+	}
+}
+
+func TestVerifyAcceptsPreviousKeys(t *testing.T) {
+	old, _ := timeflake.NewSigner(4, []byte("old"))
+	rotated, _ := timeflake.NewSigner(4, []byte("new"), []byte("old"))
+	tf, _ := timeflake.Random()
+
+	if _, err := rotated.Verify(old.Sign(tf)); err != nil {
+		t.Errorf("tokens of previous keys should be accepted: %s", err)
+	}
+	if _, err := old.Verify(rotated.Sign(tf)); err == nil {
+		t.Error("tokens of the new key should not be accepted by the old signer")
+	}
+}
+
+func TestNewSignerValidatesArguments(t *testing.T) {
+	if _, err := timeflake.NewSigner(2, []byte("secret")); err == nil {
+		t.Error("too short tags should be rejected")
+	}
+	if _, err := timeflake.NewSigner(8, nil); err == nil {
+		t.Error("empty keys should be rejected")
+	}
+}
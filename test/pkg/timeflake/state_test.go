@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+type memoryState struct {
+	ms       int64
+	reserves int
+	err      error
+}
+
+func (s *memoryState) Load() (int64, error) {
+	return s.ms, s.err
+}
+
+func (s *memoryState) Reserve(ms int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.reserves++
+	if ms > s.ms {
+		s.ms = ms
+	}
+	return nil
+}
+
+func TestStateStoreSurvivesRestartWithClockBehind(t *testing.T) {
+	store := timeflake.NewFileState(filepath.Join(t.TempDir(), "state"))
+	now := time.Unix(1611829003, 0)
+
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithStateStore(store, time.Second))
+	before, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(-time.Hour)
+	g = timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithStateStore(store, time.Second))
+	after, err := g.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Int.Cmp(&after.Int) >= 0 {
+		t.Errorf("restarted generator went below the stored timestamp: %s >= %s", before.Hex, after.Hex)
+	}
+	if d := after.Time().Sub(before.Time()); d > time.Second+time.Millisecond {
+		t.Errorf("restarted generator skipped %s, more than the reservation", d)
+	}
+}
+
+func TestStateStoreReservesAhead(t *testing.T) {
+	store := &memoryState{ms: -1}
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(timeflake.WithClock(func() time.Time { return now }), timeflake.WithStateStore(store, time.Second))
+
+	for i := 0; i < 100; i++ {
+		now = now.Add(5 * time.Millisecond)
+		if _, err := g.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.reserves != 1 {
+		t.Errorf("100 IDs within the reservation caused %d writes, want 1", store.reserves)
+	}
+	now = now.Add(time.Second)
+	g.Next()
+	if store.reserves != 2 {
+		t.Errorf("passing the reservation caused %d writes in total, want 2", store.reserves)
+	}
+}
+
+func TestStateStoreErrorsStopGeneration(t *testing.T) {
+	store := &memoryState{err: errors.New("disk full")}
+	g := timeflake.NewGenerator(timeflake.WithStateStore(store, time.Second))
+
+	f, err := g.Next()
+	var gerr *customerr.GeneratorError
+	if f != nil || !errors.As(err, &gerr) || !errors.Is(err, store.err) {
+		t.Errorf("expected a GeneratorError wrapping the store error, got %v", err)
+	}
+}
+
+func TestFileStateKeepsHighestValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	store := timeflake.NewFileState(path)
+
+	if ms, err := store.Load(); err != nil || ms != -1 {
+		t.Fatalf("Load() of a new file = %d, %v, want -1", ms, err)
+	}
+	store.Reserve(2000)
+	store.Reserve(1000)
+	if ms, _ := store.Load(); ms != 2000 {
+		t.Errorf("Load() = %d, want 2000", ms)
+	}
+}
+
+func TestFileStateConcurrentProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(ms int64) {
+			defer wg.Done()
+			// A FileState per goroutine, like separate processes.
+			if err := timeflake.NewFileState(path).Reserve(ms); err != nil {
+				t.Error(err)
+			}
+		}(int64(i) * 1000)
+	}
+	wg.Wait()
+
+	if ms, _ := timeflake.NewFileState(path).Load(); ms != 20000 {
+		t.Errorf("Load() = %d, want 20000", ms)
+	}
+}
+
+func TestFileStateRejectsCorruptedFile(t *testing.T) {
+	// The file is replaced atomically, so even an empty one is not left
+	// behind by a crash.
+	for _, content := range []string{"garbage", ""} {
+		path := filepath.Join(t.TempDir(), "state")
+		os.WriteFile(path, []byte(content), 0o644)
+
+		_, err := timeflake.NewFileState(path).Load()
+		if err == nil || !strings.Contains(err.Error(), "corrupted") {
+			t.Errorf("expected an error for %q, got %v", content, err)
+		}
+	}
+}
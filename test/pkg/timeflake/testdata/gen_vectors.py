@@ -0,0 +1,56 @@
+"""Generates vectors.json, the conformance corpus of go-timeflake.
+
+The vectors are computed with the reference implementation
+(https://github.com/anthonynsimon/timeflake), which must be installed. The
+"generator" field of the output records its version.
+
+    pip install timeflake
+    python3 gen_vectors.py > vectors.json
+"""
+import json
+import sys
+
+try:
+    import timeflake
+except ImportError:
+    sys.exit("gen_vectors.py: the timeflake package is required, pip install timeflake")
+
+MAX_TIMESTAMP = 2**48 - 1
+MAX_RANDOM = 2**80 - 1
+
+TIMESTAMPS = [0, 1, 999, 1611829003000, 2**40 - 1, 2**40, 2**47, MAX_TIMESTAMP]
+RANDOMS = [0, 1, 2**72 - 1, 985318938706034770822415, MAX_RANDOM]
+
+
+def encode(timestamp, random):
+    flake = timeflake.from_values(timestamp, random)
+    return {
+        "int": str(flake.int),
+        "hex": flake.hex,
+        "base62": flake.base62,
+        "uuid": str(flake.uuid),
+    }
+
+
+def version():
+    try:
+        from importlib.metadata import version
+
+        return version("timeflake")
+    except Exception:
+        return getattr(timeflake, "__version__", "unknown")
+
+
+def main():
+    vectors = []
+    for timestamp in TIMESTAMPS:
+        for random in RANDOMS:
+            vectors.append(
+                dict(encode(timestamp, random), timestamp=timestamp, random=str(random))
+            )
+    generator = "timeflake " + version()
+    print(json.dumps({"generator": generator, "vectors": vectors}, indent=2))
+
+
+if __name__ == "__main__":
+    main()
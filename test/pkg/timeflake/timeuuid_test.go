@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Both UUIDs are test vectors of RFC 9562 for Tuesday, February 22, 2022
+// 2:22:22.00 PM GMT-05:00.
+var timeUUIDVectors = []string{
+	"c232ab00-9414-11ec-b3c8-9f6bdeced846",
+	"1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+}
+
+func TestFromTimeUUID(t *testing.T) {
+	expected := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	for _, value := range timeUUIDVectors {
+		u := uuid.MustParse(value)
+		tf, err := timeflake.FromTimeUUID(u, true)
+		if err != nil {
+			t.Fatalf("%s: %s", value, err)
+		}
+		if !tf.Time().Equal(expected) {
+			t.Errorf("%s: expected %s got %s", value, expected, tf.Time())
+		}
+
+		again, _ := timeflake.FromTimeUUID(u, true)
+		if again.Hex != tf.Hex {
+			t.Errorf("%s: deterministic conversion differs %s != %s", value, again.Hex, tf.Hex)
+		}
+		random, _ := timeflake.FromTimeUUID(u, false)
+		if random.Hex == tf.Hex || !random.Time().Equal(expected) {
+			t.Errorf("%s: random conversion is not correct %s", value, random.Hex)
+		}
+	}
+}
+
+func TestFromTimeUUIDKeepsSubMillisecondOrder(t *testing.T) {
+	// 100ns and 200ns after the RFC 9562 vector.
+	a, _ := timeflake.FromTimeUUID(uuid.MustParse("c232ab01-9414-11ec-b3c8-9f6bdeced846"), true)
+	b, _ := timeflake.FromTimeUUID(uuid.MustParse("c232ab02-9414-11ec-0000-000000000000"), true)
+	if a.Int.Cmp(&b.Int) >= 0 {
+		t.Errorf("timeflakes should keep the order of the UUIDs %s >= %s", a.Hex, b.Hex)
+	}
+	if !a.Time().Equal(b.Time()) {
+		t.Errorf("timeflakes should share the millisecond %s != %s", a.Time(), b.Time())
+	}
+}
+
+func TestFromTimeUUIDRejectsOtherVersions(t *testing.T) {
+	if _, err := timeflake.FromTimeUUID(uuid.New(), true); err == nil {
+		t.Error("a UUIDv4 should be rejected")
+	}
+}
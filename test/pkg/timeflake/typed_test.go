@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gioni06/go-timeflake/internal/customerr"
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+type user struct{}
+
+func (user) Prefix() string { return "usr" }
+
+type order struct{}
+
+func (order) Prefix() string { return "ord" }
+
+func TestTypedIDFormatAndParse(t *testing.T) {
+	tf, _ := timeflake.FromBase62("02lVIoVLUfN6xUwLlnSRjj")
+	id := timeflake.NewTypedID[user](tf.ID())
+	if id.String() != "usr_02lVIoVLUfN6xUwLlnSRjj" {
+		t.Errorf("typed ID is not correct %s", id)
+	}
+
+	parsed, err := timeflake.ParseTypedID[user]("usr_02lVIoVLUfN6xUwLlnSRjj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != id || parsed.ID().Time() != tf.Time() {
+		t.Errorf("expected %s got %s", id, parsed)
+	}
+}
+
+func TestTypedIDRejectsUnexpectedPrefix(t *testing.T) {
+	for _, value := range []string{"ord_02lVIoVLUfN6xUwLlnSRjj", "02lVIoVLUfN6xUwLlnSRjj", "usr_invalid", "usr_"} {
+		_, err := timeflake.ParseTypedID[user](value)
+		if _, ok := err.(*customerr.ConversionError); !ok {
+			t.Errorf("%s: expected a conversion error got %v", value, err)
+		}
+	}
+}
+
+func TestTypedIDJSON(t *testing.T) {
+	type invoice struct {
+		User  timeflake.TypedID[user]  `json:"user"`
+		Order timeflake.TypedID[order] `json:"order"`
+	}
+	u, _ := timeflake.Random()
+	o, _ := timeflake.Random()
+	in := invoice{User: timeflake.NewTypedID[user](u.ID()), Order: timeflake.NewTypedID[order](o.ID())}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out invoice
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("expected %+v got %+v", in, out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"user": "ord_02lVIoVLUfN6xUwLlnSRjj"}`), &out); err == nil {
+		t.Error("an order ID should not be accepted as user ID")
+	}
+}
+
+func TestTypedIDSQL(t *testing.T) {
+	tf, _ := timeflake.Random()
+	id := timeflake.NewTypedID[order](tf.ID())
+
+	v, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned timeflake.TypedID[order]
+	if err := scanned.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != id {
+		t.Errorf("expected %s got %s", id, scanned)
+	}
+	if err := scanned.Scan([]byte(id.String())); err != nil {
+		t.Error(err)
+	}
+	if err := scanned.Scan(42); err == nil {
+		t.Error("scanning an int should fail")
+	}
+}
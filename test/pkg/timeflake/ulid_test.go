@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+// Test vectors from the ULID specification and the oklog/ulid README.
+var ulidVectors = []struct {
+	ulid string
+	hex  string
+	ms   int64
+}{
+	{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01563e3ab5d3d6764c61efb99302bd5b", 1469922850259},
+	{"01AN4Z07BY79KA1307SR9X4MV3", "015549f01d7e3a66a08c07ce13d25363", 1465824320894},
+	{"0000XSNJG0MQJHBF4QX1EFD6Y3", "00003b9aca00a5e515bc97e85cf69bc3", 1000000000},
+	{"00000000000000000000000000", "00000000000000000000000000000000", 0},
+	{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "ffffffffffffffffffffffffffffffff", 281474976710655},
+}
+
+func TestULIDVectors(t *testing.T) {
+	for _, v := range ulidVectors {
+		tf, err := timeflake.FromULIDString(v.ulid)
+		if err != nil {
+			t.Errorf("%s: %s", v.ulid, err)
+			continue
+		}
+		if tf.Hex != v.hex {
+			t.Errorf("%s: expected hex %s got %s", v.ulid, v.hex, tf.Hex)
+		}
+		if !tf.Time().Equal(time.Unix(v.ms/1000, v.ms%1000*int64(time.Millisecond))) {
+			t.Errorf("%s: time is not correct %s", v.ulid, tf.Time())
+		}
+		if tf.ToULIDString() != v.ulid {
+			t.Errorf("%s: round trip returned %s", v.ulid, tf.ToULIDString())
+		}
+	}
+}
+
+func TestULIDDecodingIsLenient(t *testing.T) {
+	tf, err := timeflake.FromULIDString("01arz3ndektsv4rrffq69g5fav")
+	if err != nil || tf.ToULIDString() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("lower case ULID should be accepted: %v", err)
+	}
+	tf, err = timeflake.FromULIDString("O1ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil || tf.ToULIDString() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("O should be decoded as 0: %v", err)
+	}
+}
+
+func TestULIDRejectsInvalidInput(t *testing.T) {
+	for _, value := range []string{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "01ARZ3NDEKTSV4RRFFQ69G5FAU", ""} {
+		if _, err := timeflake.FromULIDString(value); err == nil {
+			t.Errorf("'%s' should be rejected", value)
+		}
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	tf, err := timeflake.Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tf.Hex != "01563e3ab5d3d6764c61efb99302bd5b" {
+		t.Errorf("ULID was not parsed correctly %s", tf.Hex)
+	}
+
+	// ULIDs and Timeflakes of the same time sort together.
+	f, _ := timeflake.MinForTime(tf.Time())
+	if f.ToULIDString() >= tf.ToULIDString() {
+		t.Errorf("ULIDs should sort like timeflakes %s >= %s", f.ToULIDString(), tf.ToULIDString())
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gioni06/go-timeflake/pkg/timeflake"
+)
+
+func TestGeneratorWithUUIDv7(t *testing.T) {
+	now := time.Unix(1611829003, 0)
+	g := timeflake.NewGenerator(
+		timeflake.WithUUIDv7(),
+		timeflake.WithClock(func() time.Time { return now }),
+	)
+
+	var prev *timeflake.Timeflake
+	for i := 0; i < 1000; i++ {
+		f, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := uuid.Parse(f.UUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Version() != 7 || u.Variant() != uuid.RFC4122 {
+			t.Fatalf("%s is not a UUIDv7, version %d variant %s", f.UUID, u.Version(), u.Variant())
+		}
+		if !f.IsUUIDv7() {
+			t.Fatalf("%s should be detected as UUIDv7", f.UUID)
+		}
+		if !f.Time().Equal(now) {
+			t.Fatalf("timeflake time is not correct %s", f.Time())
+		}
+		if prev != nil && prev.Int.Cmp(&f.Int) >= 0 {
+			t.Fatalf("timeflakes are not increasing: %s >= %s", prev.UUID, f.UUID)
+		}
+		prev = f
+	}
+}
+
+func TestToUUIDv7(t *testing.T) {
+	tf, _ := timeflake.FromHex("0177487ec2f8d0a63f2785a9cadfc50f")
+	if tf.IsUUIDv7() {
+		t.Error("timeflake should not be detected as UUIDv7")
+	}
+
+	u, err := tf.ToUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "0177487e-c2f8-7d0a-98fc-9e16a72b7f14" {
+		t.Errorf("UUIDv7 is not correct %s", u)
+	}
+
+	parsed, err := uuid.Parse(u.String())
+	if err != nil || parsed.Version() != 7 || parsed.Variant() != uuid.RFC4122 {
+		t.Errorf("%s is not a valid UUIDv7", u)
+	}
+
+	f, err := timeflake.FromUUIDv7(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.IsUUIDv7() || f.UUID != u.String() || !f.Time().Equal(tf.Time()) {
+		t.Errorf("conversion should keep the UUID and time %s %s", f.UUID, f.Time())
+	}
+	if again, _ := f.ToUUIDv7(); again != u || again.String() != f.UUID {
+		t.Errorf("a UUIDv7 should be returned unchanged %s", again)
+	}
+}
+
+func TestToUUIDv7PreservesTimeOrdering(t *testing.T) {
+	a, _ := timeflake.FromHex("0177487ec2f8ffffffffffffffffffff")
+	b, _ := timeflake.FromHex("0177487ec2f900000000000000000000")
+
+	ua, _ := a.ToUUIDv7()
+	ub, _ := b.ToUUIDv7()
+	if ua.String() >= ub.String() {
+		t.Errorf("UUIDv7 should keep the time ordering %s >= %s", ua, ub)
+	}
+}
+
+func TestToUUIDv7PreservesOrderWithinMillisecond(t *testing.T) {
+	var prev uuid.UUID
+	for i, h := range []string{
+		"0177487ec2f800000000000000000000",
+		"0177487ec2f80000000000000000003f",
+		"0177487ec2f80000000000000000f000",
+		"0177487ec2f8003fffffffffffffffff",
+		"0177487ec2f8004000000000000000ff",
+		"0177487ec2f8ffffffffffffffffffff",
+	} {
+		tf, _ := timeflake.FromHex(h)
+		u, err := tf.ToUUIDv7()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && bytes.Compare(prev[:], u[:]) > 0 {
+			t.Errorf("UUIDv7 of %s is lower than the one before: %s < %s", h, u, prev)
+		}
+		prev = u
+	}
+}
+
+func TestFromUUIDv7RejectsOtherVersions(t *testing.T) {
+	if _, err := timeflake.FromUUIDv7(uuid.New()); err == nil {
+		t.Error("a UUIDv4 should be rejected")
+	}
+}